@@ -0,0 +1,129 @@
+package iterator
+
+// BidiIterator is an Iterator able to move backward as well. Moving past either end stays right past it,
+// so that moving back from there reaches the first or the last element again.
+type BidiIterator[E any] interface {
+	Iterator[E]
+	// MovePrev moves to the previous element, returns true if the previous element exists and false otherwise.
+	MovePrev() bool
+}
+
+// Seeker is implemented by Iterators over random-access sources, which jump to any position in O(1).
+type Seeker interface {
+	// Seek sets the position to pos clamped into [0, Len()], so that the next MoveNext moves to the element at pos.
+	Seek(pos int)
+	// Pos returns the index of the element the next MoveNext moves to, which is Len() once exhausted.
+	Pos() int
+	// Len returns the count of all elements.
+	Len() int
+}
+
+// ReversibleIterable is implemented by Iterables able to iterate backward without buffering.
+type ReversibleIterable[E any] interface {
+	Iterable[E]
+	// Reverse returns an Iterable of the same elements in reverse order.
+	Reverse() Iterable[E]
+}
+
+// Reversed returns an Iterable of elements of iterable in reverse order. It iterates backward without buffering
+// if iterable is a ReversibleIterable, and buffers all elements on every Iterator otherwise.
+func Reversed[E any](iterable Iterable[E]) Iterable[E] {
+	if r, ok := iterable.(ReversibleIterable[E]); ok {
+		return r.Reverse()
+	}
+	return bufferedReverseIterable[E]{inner: iterable}
+}
+
+// region seekReverseIterable
+
+// seekReverseIterable reverses an Iterable, whose Iterators are all Seekers.
+type seekReverseIterable[E any] struct {
+	inner Iterable[E]
+}
+
+func (s seekReverseIterable[E]) Iterator() Iterator[E] {
+	iter := s.inner.Iterator()
+	sk := iter.(Seeker)
+	return &seekReverseIterator[E]{inner: iter, sk: sk, left: sk.Len()}
+}
+
+func (s seekReverseIterable[E]) Size() (n uint64, known bool) {
+	return s.inner.Size()
+}
+
+func (s seekReverseIterable[E]) Reverse() Iterable[E] {
+	return s.inner
+}
+
+type seekReverseIterator[E any] struct {
+	inner Iterator[E]
+	sk    Seeker
+	left  int // count of elements not moved to yet.
+}
+
+func (s *seekReverseIterator[E]) MoveNext() bool {
+	if s.left == 0 {
+		return false
+	}
+	s.left--
+	s.sk.Seek(s.left)
+	return s.inner.MoveNext()
+}
+
+func (s *seekReverseIterator[E]) Current() E {
+	return s.inner.Current()
+}
+
+func (s *seekReverseIterator[E]) Close() {
+	s.inner.Close()
+}
+
+func (s *seekReverseIterator[E]) Seek(pos int) {
+	s.left = s.sk.Len() - clamp(pos, s.sk.Len())
+}
+
+func (s *seekReverseIterator[E]) Pos() int {
+	return s.sk.Len() - s.left
+}
+
+func (s *seekReverseIterator[E]) Len() int {
+	return s.sk.Len()
+}
+
+// clamp clamps pos into [0, n].
+func clamp(pos, n int) int {
+	if pos < 0 {
+		return 0
+	} else if pos > n {
+		return n
+	}
+	return pos
+}
+
+// endregion
+
+// region bufferedReverseIterable
+
+type bufferedReverseIterable[E any] struct {
+	inner Iterable[E]
+}
+
+func (b bufferedReverseIterable[E]) Iterator() Iterator[E] {
+	var slc []E
+	iter := b.inner.Iterator()
+	defer iter.Close()
+	for iter.MoveNext() {
+		slc = append(slc, iter.Current())
+	}
+	return SliceIterable[E](slc).Reverse().Iterator()
+}
+
+func (b bufferedReverseIterable[E]) Size() (n uint64, known bool) {
+	return b.inner.Size()
+}
+
+func (b bufferedReverseIterable[E]) Reverse() Iterable[E] {
+	return b.inner
+}
+
+// endregion
@@ -0,0 +1,97 @@
+package iterator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSliceIteratorBidi(t *testing.T) {
+	iter := SliceIterator([]int{0, 1, 2}).(interface {
+		BidiIterator[int]
+		Seeker
+	})
+	var moves []any
+	for iter.MoveNext() {
+		moves = append(moves, iter.Current())
+	}
+	moves = append(moves, iter.Pos())
+	for iter.MovePrev() {
+		moves = append(moves, iter.Current())
+	}
+	moves = append(moves, iter.Pos())
+	iter.Seek(2)
+	iter.MoveNext()
+	moves = append(moves, iter.Current(), iter.Pos(), iter.Len())
+	iter.Seek(100)
+	moves = append(moves, iter.MoveNext(), iter.Pos())
+	if expected := []any{0, 1, 2, 3, 2, 1, 0, 0, 2, 3, 3, false, 3}; !reflect.DeepEqual(expected, moves) {
+		t.Fatalf("expected: %v, actual: %v\n", expected, moves)
+	}
+}
+
+func TestBytesIteratorSeek(t *testing.T) {
+	bs := []byte{1, 0, 2, 0, 3, 0, 9}
+	iter := BytesIterator[uint16](bs).(interface {
+		BidiIterator[uint16]
+		Seeker
+	})
+	if iter.Len() != 3 {
+		t.Fatalf("case: len, expected: %v, actual: %v\n", 3, iter.Len())
+	}
+	iter.Seek(1)
+	iter.MoveNext()
+	iter.MoveNext()
+	if iter.Current() != 3 || iter.MoveNext() || !iter.MovePrev() || iter.Current() != 3 {
+		t.Fatalf("case: seek, actual: %v\n", iter.Current())
+	}
+	if actual := drain(Reversed(BytesIterable[uint16](bs)).Iterator()); !reflect.DeepEqual([]uint16{3, 2, 1}, actual) {
+		t.Fatalf("case: reversed, actual: %v\n", actual)
+	}
+}
+
+func TestRuneIteratorMovePrev(t *testing.T) {
+	iter := StringRuneIterator("a我b")
+	for iter.MoveNext() {
+	}
+	var runes []rune
+	var offsets []int
+	for iter.MovePrev() {
+		runes = append(runes, iter.Current())
+		offsets = append(offsets, iter.Offset())
+	}
+	if string(runes) != "b我a" || !reflect.DeepEqual([]int{4, 1, 0}, offsets) {
+		t.Fatalf("actual: %v, %v\n", string(runes), offsets)
+	}
+	if !iter.MoveNext() || iter.Current() != 'a' {
+		t.Fatalf("case: move next again, actual: %v\n", string(iter.Current()))
+	}
+}
+
+type onceIterable[E any] []E
+
+func (o onceIterable[E]) Iterator() Iterator[E] {
+	return Filter(SliceIterator(o), func(E) bool { return true })
+}
+
+func (o onceIterable[E]) Size() (n uint64, known bool) {
+	return uint64(len(o)), true
+}
+
+func TestReversed(t *testing.T) {
+	slc := SliceIterable[int]{0, 1, 2, 3}
+	reversed := Reversed[int](slc)
+	if actual := drain(reversed.Iterator()); !reflect.DeepEqual([]int{3, 2, 1, 0}, actual) {
+		t.Fatalf("case: slice, actual: %v\n", actual)
+	}
+	if actual := drain(Reversed(reversed).Iterator()); !reflect.DeepEqual([]int{0, 1, 2, 3}, actual) {
+		t.Fatalf("case: twice, actual: %v\n", actual)
+	}
+	iter := reversed.Iterator()
+	iter.(Seeker).Seek(2)
+	if !iter.MoveNext() || iter.Current() != 1 || iter.(Seeker).Pos() != 3 {
+		t.Fatalf("case: seek reversed, actual: %v\n", iter.Current())
+	}
+	if actual := drain(Reversed[int](onceIterable[int]{0, 1, 2}).Iterator()); !reflect.DeepEqual([]int{2, 1, 0}, actual) {
+		t.Fatalf("case: buffered, actual: %v\n", actual)
+	}
+}
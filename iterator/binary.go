@@ -0,0 +1,184 @@
+package iterator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrPartialRecord is wrapped by the error of binary Iterators, whose input ends in the middle of a record.
+var ErrPartialRecord = errors.New("iterator: trailing partial record")
+
+// Binary returns an Iterable decoding records of E from data one after another in the given order by
+// encoding/binary, so that neither the native endianness nor the alignment matters. E must be fixed-size, i.e.
+// a fixed-size number, or an array or a struct of them. Its Iterators are ErrIterators, which stop with an error
+// wrapping ErrPartialRecord if data ends with a partial record, while Size counts whole records only.
+func Binary[E any](data []byte, order binary.ByteOrder) Iterable[E] {
+	return binaryIterable[E]{data: data, order: order, recSz: recordSize[E]()}
+}
+
+// BinaryReader returns an Iterable decoding records of E from r like Binary. Its Iterators read r from where it
+// is, and stop with the error of r, or an error wrapping ErrPartialRecord if r ends in the middle of a record.
+func BinaryReader[E any](r io.Reader, order binary.ByteOrder) Iterable[E] {
+	return binaryReaderIterable[E]{r: r, order: order, recSz: recordSize[E]()}
+}
+
+// recordSize returns the encoded size of E, and panics if E is not fixed-size.
+func recordSize[E any]() int {
+	var e E
+	sz := binary.Size(e)
+	if sz <= 0 {
+		panic(fmt.Sprintf("%T is not a non-empty fixed-size type to decode by encoding/binary", e))
+	}
+	return sz
+}
+
+// decodeRecord decodes rec into v in the given order, where rd is reused to save allocations.
+func decodeRecord[E any](rd *bytes.Reader, rec []byte, order binary.ByteOrder, v *E) {
+	rd.Reset(rec)
+	if err := binary.Read(rd, order, v); err != nil {
+		// unreachable since rec is of the encoded size of E.
+		panic(err)
+	}
+}
+
+func partialRecord(n, recSz int) error {
+	return fmt.Errorf("%w: %d of %d bytes", ErrPartialRecord, n, recSz)
+}
+
+// region binaryIterable
+
+type binaryIterable[E any] struct {
+	data  []byte
+	order binary.ByteOrder
+	recSz int
+}
+
+func (b binaryIterable[E]) Iterator() Iterator[E] {
+	return &binaryIterator[E]{binaryIterable: b, idx: -1}
+}
+
+func (b binaryIterable[E]) Size() (n uint64, known bool) {
+	return uint64(len(b.data) / b.recSz), true
+}
+
+type binaryIterator[E any] struct {
+	binaryIterable[E]
+	idx  int // index of the current record, -1 before the first one.
+	rd   bytes.Reader
+	curr E
+	err  error
+}
+
+func (b *binaryIterator[E]) load() {
+	decodeRecord(&b.rd, b.data[b.idx*b.recSz:(b.idx+1)*b.recSz], b.order, &b.curr)
+}
+
+func (b *binaryIterator[E]) MoveNext() bool {
+	if b.idx+1 >= b.Len() {
+		b.idx = b.Len()
+		if rem := len(b.data) % b.recSz; rem != 0 {
+			b.err = partialRecord(rem, b.recSz)
+		}
+		return false
+	}
+	b.idx++
+	b.load()
+	return true
+}
+
+func (b *binaryIterator[E]) MovePrev() bool {
+	if b.idx-1 < 0 {
+		b.idx = -1
+		return false
+	}
+	b.idx--
+	b.load()
+	return true
+}
+
+func (b *binaryIterator[E]) Current() E {
+	return b.curr
+}
+
+func (b *binaryIterator[E]) Close() {}
+
+func (b *binaryIterator[E]) Err() error {
+	return b.err
+}
+
+func (b *binaryIterator[E]) Seek(pos int) {
+	b.idx = clamp(pos, b.Len()) - 1
+}
+
+func (b *binaryIterator[E]) Pos() int {
+	return clamp(b.idx+1, b.Len())
+}
+
+// Len returns the count of whole records, where a trailing partial record is not counted.
+func (b *binaryIterator[E]) Len() int {
+	return len(b.data) / b.recSz
+}
+
+// endregion
+
+// region binaryReaderIterable
+
+type binaryReaderIterable[E any] struct {
+	r     io.Reader
+	order binary.ByteOrder
+	recSz int
+}
+
+func (b binaryReaderIterable[E]) Iterator() Iterator[E] {
+	return &binaryReaderIterator[E]{r: b.r, order: b.order, buf: make([]byte, b.recSz)}
+}
+
+func (b binaryReaderIterable[E]) Size() (n uint64, known bool) {
+	return 0, false
+}
+
+type binaryReaderIterator[E any] struct {
+	r     io.Reader
+	order binary.ByteOrder
+	buf   []byte
+	rd    bytes.Reader
+	curr  E
+	err   error
+	done  bool
+}
+
+func (b *binaryReaderIterator[E]) MoveNext() bool {
+	if b.done {
+		return false
+	}
+	n, err := io.ReadFull(b.r, b.buf)
+	switch {
+	case err == nil:
+		decodeRecord(&b.rd, b.buf, b.order, &b.curr)
+		return true
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		b.err = partialRecord(n, len(b.buf))
+	case !errors.Is(err, io.EOF):
+		b.err = err
+	}
+	b.done = true
+	return false
+}
+
+func (b *binaryReaderIterator[E]) Current() E {
+	return b.curr
+}
+
+// Close stops the Iterator, but leaves r open, which belongs to the caller.
+func (b *binaryReaderIterator[E]) Close() {
+	b.done = true
+}
+
+func (b *binaryReaderIterator[E]) Err() error {
+	return b.err
+}
+
+// endregion
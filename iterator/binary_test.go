@@ -0,0 +1,99 @@
+package iterator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type frame struct {
+	ID    uint16
+	Temp  int32
+	Flags [2]uint8
+}
+
+func collectErr[E any](iter Iterator[E]) ([]E, error) {
+	defer iter.Close()
+	var ret []E
+	for iter.MoveNext() {
+		ret = append(ret, iter.Current())
+	}
+	return ret, Err(iter)
+}
+
+func TestBinary(t *testing.T) {
+	data := []byte{0x00, 0x01, 0x00, 0x02}
+	if actual, err := collectErr(Binary[uint16](data, binary.BigEndian).Iterator()); err != nil ||
+		!reflect.DeepEqual([]uint16{1, 2}, actual) {
+		t.Fatalf("case: big endian, actual: %v, err: %v\n", actual, err)
+	}
+	if actual, err := collectErr(Binary[uint16](data, binary.LittleEndian).Iterator()); err != nil ||
+		!reflect.DeepEqual([]uint16{0x100, 0x200}, actual) {
+		t.Fatalf("case: little endian, actual: %v, err: %v\n", actual, err)
+	}
+
+	var buf bytes.Buffer
+	frames := []frame{{1, -40, [2]uint8{1, 0}}, {2, 85, [2]uint8{0, 1}}}
+	_ = binary.Write(&buf, binary.LittleEndian, frames)
+	// unaligned and followed by a partial frame.
+	data = append([]byte{0xff}, append(buf.Bytes(), 0x03, 0x00)...)[1:]
+	iterable := Binary[frame](data, binary.LittleEndian)
+	if n, known := iterable.Size(); n != 2 || !known {
+		t.Fatalf("case: size, actual: %v, %v\n", n, known)
+	}
+	actual, err := collectErr(iterable.Iterator())
+	if !reflect.DeepEqual(frames, actual) || !errors.Is(err, ErrPartialRecord) {
+		t.Fatalf("case: struct, actual: %v, err: %v\n", actual, err)
+	}
+
+	iter := iterable.Iterator()
+	iter.(Seeker).Seek(1)
+	if !iter.MoveNext() || iter.Current() != frames[1] || !iter.(BidiIterator[frame]).MovePrev() ||
+		iter.Current() != frames[0] {
+		t.Fatalf("case: seek\n")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("case: not fixed-size, expected a panic\n")
+		}
+	}()
+	Binary[[]int](data, binary.LittleEndian)
+}
+
+type failingReader struct {
+	data []byte
+	err  error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if len(f.data) == 0 {
+		return 0, f.err
+	}
+	n := copy(p, f.data)
+	f.data = f.data[n:]
+	return n, nil
+}
+
+func TestBinaryReader(t *testing.T) {
+	data := []byte{0x01, 0x00, 0x02, 0x00, 0x03}
+	actual, err := collectErr(BinaryReader[int16](bytes.NewReader(data), binary.LittleEndian).Iterator())
+	if !reflect.DeepEqual([]int16{1, 2}, actual) || !errors.Is(err, ErrPartialRecord) {
+		t.Fatalf("case: partial, actual: %v, err: %v\n", actual, err)
+	}
+	actual, err = collectErr(BinaryReader[int16](bytes.NewReader(data[:4]), binary.LittleEndian).Iterator())
+	if !reflect.DeepEqual([]int16{1, 2}, actual) || err != nil {
+		t.Fatalf("case: whole, actual: %v, err: %v\n", actual, err)
+	}
+	broken := errors.New("broken")
+	actual, err = collectErr(BinaryReader[int16](&failingReader{data: data[:2], err: broken}, binary.LittleEndian).Iterator())
+	if !reflect.DeepEqual([]int16{1}, actual) || err != broken {
+		t.Fatalf("case: reader error, actual: %v, err: %v\n", actual, err)
+	}
+	prefetched := Prefetch(BinaryReader[int16](bytes.NewReader(data), binary.LittleEndian).Iterator(), 1)
+	if _, err = collectErr(prefetched); !errors.Is(err, ErrPartialRecord) {
+		t.Fatalf("case: prefetched, err: %v\n", err)
+	}
+}
@@ -1,22 +1,21 @@
 package iterator
 
 import (
-	"reflect"
 	"unsafe"
 )
 
 type bytesIterable[E comparable] struct {
-	data   uintptr
+	data   unsafe.Pointer
 	len    int
 	elemSz int
 }
 
 type bytesIterator[E comparable] struct {
 	EmptyIterator[E]
-	data   uintptr
+	data   unsafe.Pointer
 	len    int
 	elemSz int
-	offset int
+	idx    int // index of the current element, -1 before the first one.
 	curr   E
 }
 
@@ -24,12 +23,11 @@ func BytesIterable[E comparable, BS ~[]byte](bytes BS) Iterable[E] {
 	if len(bytes) == 0 {
 		return EmptyIterable[E]{}
 	}
-	slcH := *(*reflect.SliceHeader)(unsafe.Pointer(&bytes))
 	var e E
 	elemSz := int(unsafe.Sizeof(e))
 	return &bytesIterable[E]{
-		data:   slcH.Data,
-		len:    slcH.Len,
+		data:   unsafe.Pointer(&bytes[0]),
+		len:    len(bytes),
 		elemSz: elemSz,
 	}
 }
@@ -38,22 +36,31 @@ func StringIterable[E comparable](str string) Iterable[E] {
 	if len(str) == 0 {
 		return EmptyIterable[E]{}
 	}
-	strH := *(*reflect.SliceHeader)(unsafe.Pointer(&str))
 	var e E
 	elemSz := int(unsafe.Sizeof(e))
 	return &bytesIterable[E]{
-		data:   strH.Data,
-		len:    strH.Len,
+		data:   stringData(str),
+		len:    len(str),
 		elemSz: elemSz,
 	}
 }
 
+// stringData returns the pointer to the bytes of str, which keeps them alive unlike a uintptr.
+func stringData(str string) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&str))
+}
+
+// stringBytes returns the bytes of str without copying, which must not be modified.
+func stringBytes(str string) []byte {
+	return unsafe.Slice((*byte)(stringData(str)), len(str))
+}
+
 func (b bytesIterable[E]) Iterator() Iterator[E] {
 	return &bytesIterator[E]{
 		data:   b.data,
 		len:    b.len,
 		elemSz: b.elemSz,
-		offset: 0,
+		idx:    -1,
 	}
 }
 
@@ -68,14 +75,13 @@ func BytesIterator[E comparable, BS ~[]byte](bytes BS) Iterator[E] {
 	if len(bytes) == 0 {
 		return EmptyIterator[E]{}
 	}
-	slcH := *(*reflect.SliceHeader)(unsafe.Pointer(&bytes))
 	var e E
 	elemSz := int(unsafe.Sizeof(e))
 	return &bytesIterator[E]{
-		data:   slcH.Data,
-		len:    slcH.Len,
+		data:   unsafe.Pointer(&bytes[0]),
+		len:    len(bytes),
 		elemSz: elemSz,
-		offset: 0,
+		idx:    -1,
 	}
 }
 
@@ -83,27 +89,58 @@ func BytesIterator[E comparable, BS ~[]byte](bytes BS) Iterator[E] {
 // Note that, StringIterator[rune] does not iterate elements decoded in utf-8 rune.
 // For that case, you should use StringRuneIterator.
 func StringIterator[E comparable](str string) Iterator[E] {
-	strH := *(*reflect.SliceHeader)(unsafe.Pointer(&str))
 	var e E
 	elemSz := int(unsafe.Sizeof(e))
 	return &bytesIterator[E]{
-		data:   strH.Data,
-		len:    strH.Len,
+		data:   stringData(str),
+		len:    len(str),
 		elemSz: elemSz,
-		offset: 0,
+		idx:    -1,
 	}
 }
 
+// Reverse returns an Iterable of elements of the bytes in reverse order.
+func (b bytesIterable[E]) Reverse() Iterable[E] {
+	return seekReverseIterable[E]{inner: b}
+}
+
+func (s *bytesIterator[E]) load() {
+	s.curr = *(*E)(unsafe.Add(s.data, s.idx*s.elemSz))
+}
+
 func (s *bytesIterator[E]) MoveNext() bool {
-	if s.offset >= s.len {
+	if s.idx+1 >= s.Len() {
+		s.idx = s.Len()
 		return false
 	}
-	//goland:noinspection GoVetUnsafePointer
-	s.curr = *(*E)(unsafe.Pointer(s.data + uintptr(s.offset)))
-	s.offset += s.elemSz
+	s.idx++
+	s.load()
 	return true
 }
 
+func (s *bytesIterator[E]) MovePrev() bool {
+	if s.idx-1 < 0 {
+		s.idx = -1
+		return false
+	}
+	s.idx--
+	s.load()
+	return true
+}
+
+func (s *bytesIterator[E]) Seek(pos int) {
+	s.idx = clamp(pos, s.Len()) - 1
+}
+
+func (s *bytesIterator[E]) Pos() int {
+	return clamp(s.idx+1, s.Len())
+}
+
+// Len returns the count of whole elements, where a trailing partial element is not counted.
+func (s *bytesIterator[E]) Len() int {
+	return s.len / s.elemSz
+}
+
 func (s *bytesIterator[E]) Current() E {
 	return s.curr
 }
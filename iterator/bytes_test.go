@@ -1,6 +1,8 @@
 package iterator
 
 import (
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -25,3 +27,29 @@ func FuzzStringIterator(f *testing.F) {
 		}
 	})
 }
+
+func TestBytesIteratorPartial(t *testing.T) {
+	iter := BytesIterator[uint16]([]byte{1, 0, 2})
+	defer iter.Close()
+	var cnt int
+	for iter.MoveNext() {
+		if iter.Current() != 1 {
+			t.Fatalf("expected: %v, actual: %v\n", 1, iter.Current())
+		}
+		cnt++
+	}
+	if cnt != 1 {
+		t.Fatalf("expected: %v, actual: %v\n", 1, cnt)
+	}
+}
+
+func TestBytesIteratorKeepsDataAlive(t *testing.T) {
+	iter := StringIterator[byte](strings.Repeat("s", 1<<20))
+	defer iter.Close()
+	runtime.GC()
+	for i := 0; iter.MoveNext(); i++ {
+		if iter.Current() != 's' {
+			t.Fatalf("unexpected byte: %v at %v\n", iter.Current(), i)
+		}
+	}
+}
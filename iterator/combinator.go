@@ -0,0 +1,341 @@
+package iterator
+
+// Pair holds two values of possibly different types.
+type Pair[F any, S any] struct {
+	First  F
+	Second S
+}
+
+// region Map
+
+// Map returns an Iterator of mapper(v) for every v of iter.
+func Map[S any, T any](iter Iterator[S], mapper func(v S) T) Iterator[T] {
+	return &mapIterator[S, T]{inner: iter, mapper: mapper}
+}
+
+type mapIterator[S any, T any] struct {
+	inner  Iterator[S]
+	mapper func(v S) T
+	curr   T
+}
+
+func (m *mapIterator[S, T]) MoveNext() bool {
+	if !m.inner.MoveNext() {
+		return false
+	}
+	m.curr = m.mapper(m.inner.Current())
+	return true
+}
+
+func (m *mapIterator[S, T]) Current() T {
+	return m.curr
+}
+
+func (m *mapIterator[S, T]) Close() {
+	m.inner.Close()
+}
+
+// endregion
+
+// region Filter
+
+// Filter returns an Iterator of elements v of iter satisfying pred(v) == true.
+func Filter[E any](iter Iterator[E], pred func(v E) bool) Iterator[E] {
+	return &filterIterator[E]{inner: iter, pred: pred}
+}
+
+type filterIterator[E any] struct {
+	inner Iterator[E]
+	pred  func(v E) bool
+}
+
+func (f *filterIterator[E]) MoveNext() bool {
+	for f.inner.MoveNext() {
+		if f.pred(f.inner.Current()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *filterIterator[E]) Current() E {
+	return f.inner.Current()
+}
+
+func (f *filterIterator[E]) Close() {
+	f.inner.Close()
+}
+
+// endregion
+
+// region Take
+
+// Take returns an Iterator of the first n elements of iter.
+func Take[E any](iter Iterator[E], n uint64) Iterator[E] {
+	return &takeIterator[E]{inner: iter, n: n}
+}
+
+type takeIterator[E any] struct {
+	inner Iterator[E]
+	n     uint64
+}
+
+func (t *takeIterator[E]) MoveNext() bool {
+	if t.n == 0 {
+		return false
+	}
+	t.n--
+	return t.inner.MoveNext()
+}
+
+func (t *takeIterator[E]) Current() E {
+	return t.inner.Current()
+}
+
+func (t *takeIterator[E]) Close() {
+	t.inner.Close()
+}
+
+// endregion
+
+// region Skip
+
+// Skip returns an Iterator of elements of iter but the first n.
+func Skip[E any](iter Iterator[E], n uint64) Iterator[E] {
+	return &skipIterator[E]{inner: iter, n: n}
+}
+
+type skipIterator[E any] struct {
+	inner Iterator[E]
+	n     uint64
+}
+
+func (s *skipIterator[E]) MoveNext() bool {
+	for ; s.n > 0; s.n-- {
+		if !s.inner.MoveNext() {
+			s.n = 0
+			return false
+		}
+	}
+	return s.inner.MoveNext()
+}
+
+func (s *skipIterator[E]) Current() E {
+	return s.inner.Current()
+}
+
+func (s *skipIterator[E]) Close() {
+	s.inner.Close()
+}
+
+// endregion
+
+// region Chain
+
+// Chain returns an Iterator of elements of all iters one after another. Each of iters is closed once exhausted,
+// and the rest are closed when the returned Iterator is closed.
+func Chain[E any](iters ...Iterator[E]) Iterator[E] {
+	return &chainIterator[E]{iters: iters}
+}
+
+type chainIterator[E any] struct {
+	iters []Iterator[E]
+}
+
+func (c *chainIterator[E]) MoveNext() bool {
+	for len(c.iters) > 0 {
+		if c.iters[0].MoveNext() {
+			return true
+		}
+		c.iters[0].Close()
+		c.iters = c.iters[1:]
+	}
+	return false
+}
+
+func (c *chainIterator[E]) Current() E {
+	return c.iters[0].Current()
+}
+
+func (c *chainIterator[E]) Close() {
+	iters := c.iters
+	c.iters = nil
+	closeAll(iters)
+}
+
+// closeAll closes all iters, even if some of them panic.
+func closeAll[E any](iters []Iterator[E]) {
+	if len(iters) == 0 {
+		return
+	}
+	defer closeAll(iters[1:])
+	iters[0].Close()
+}
+
+// endregion
+
+// region Zip
+
+// Zip returns an Iterator of pairs of elements of a and b at the same positions, which stops once either stops.
+func Zip[A any, B any](a Iterator[A], b Iterator[B]) Iterator[Pair[A, B]] {
+	return &zipIterator[A, B]{a: a, b: b}
+}
+
+type zipIterator[A any, B any] struct {
+	a Iterator[A]
+	b Iterator[B]
+}
+
+func (z *zipIterator[A, B]) MoveNext() bool {
+	return z.a.MoveNext() && z.b.MoveNext()
+}
+
+func (z *zipIterator[A, B]) Current() Pair[A, B] {
+	return Pair[A, B]{z.a.Current(), z.b.Current()}
+}
+
+func (z *zipIterator[A, B]) Close() {
+	defer z.b.Close()
+	z.a.Close()
+}
+
+// endregion
+
+// region Enumerate
+
+// Enumerate returns an Iterator of pairs of the 0-based index and each element of iter.
+func Enumerate[E any](iter Iterator[E]) Iterator[Pair[int, E]] {
+	return &enumerateIterator[E]{inner: iter, idx: -1}
+}
+
+type enumerateIterator[E any] struct {
+	inner Iterator[E]
+	idx   int
+}
+
+func (e *enumerateIterator[E]) MoveNext() bool {
+	if !e.inner.MoveNext() {
+		return false
+	}
+	e.idx++
+	return true
+}
+
+func (e *enumerateIterator[E]) Current() Pair[int, E] {
+	return Pair[int, E]{e.idx, e.inner.Current()}
+}
+
+func (e *enumerateIterator[E]) Close() {
+	e.inner.Close()
+}
+
+// endregion
+
+// region Flatten
+
+// Flatten returns an Iterator of elements of all Iterators of iter one after another. Each inner Iterator is
+// closed once exhausted, and the current one is closed along with iter when the returned Iterator is closed.
+func Flatten[E any](iter Iterator[Iterator[E]]) Iterator[E] {
+	return &flattenIterator[E]{outer: iter}
+}
+
+type flattenIterator[E any] struct {
+	outer Iterator[Iterator[E]]
+	inner Iterator[E]
+}
+
+func (f *flattenIterator[E]) MoveNext() bool {
+	for {
+		if f.inner != nil {
+			if f.inner.MoveNext() {
+				return true
+			}
+			inner := f.inner
+			f.inner = nil
+			inner.Close()
+		}
+		if !f.outer.MoveNext() {
+			return false
+		}
+		f.inner = f.outer.Current()
+	}
+}
+
+func (f *flattenIterator[E]) Current() E {
+	return f.inner.Current()
+}
+
+func (f *flattenIterator[E]) Close() {
+	defer f.outer.Close()
+	if f.inner != nil {
+		inner := f.inner
+		f.inner = nil
+		inner.Close()
+	}
+}
+
+// endregion
+
+// region Dedup
+
+// Dedup returns an Iterator of elements of iter, but drops each element equal to the one right before it.
+func Dedup[E comparable](iter Iterator[E]) Iterator[E] {
+	return &dedupIterator[E]{inner: iter}
+}
+
+type dedupIterator[E comparable] struct {
+	inner Iterator[E]
+	curr  E
+	begun bool
+}
+
+func (d *dedupIterator[E]) MoveNext() bool {
+	for d.inner.MoveNext() {
+		v := d.inner.Current()
+		if !d.begun || v != d.curr {
+			d.curr, d.begun = v, true
+			return true
+		}
+	}
+	return false
+}
+
+func (d *dedupIterator[E]) Current() E {
+	return d.curr
+}
+
+func (d *dedupIterator[E]) Close() {
+	d.inner.Close()
+}
+
+// endregion
+
+// region Inspect
+
+// Inspect returns an Iterator of elements of iter, calling act on each element as it's moved to.
+func Inspect[E any](iter Iterator[E], act func(v E)) Iterator[E] {
+	return &inspectIterator[E]{inner: iter, act: act}
+}
+
+type inspectIterator[E any] struct {
+	inner Iterator[E]
+	act   func(v E)
+}
+
+func (i *inspectIterator[E]) MoveNext() bool {
+	if !i.inner.MoveNext() {
+		return false
+	}
+	i.act(i.inner.Current())
+	return true
+}
+
+func (i *inspectIterator[E]) Current() E {
+	return i.inner.Current()
+}
+
+func (i *inspectIterator[E]) Close() {
+	i.inner.Close()
+}
+
+// endregion
@@ -0,0 +1,79 @@
+package iterator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func drain[E any](iter Iterator[E]) (ret []E) {
+	defer iter.Close()
+	for iter.MoveNext() {
+		ret = append(ret, iter.Current())
+	}
+	return
+}
+
+func counted[E any](slc ...E) *closeCounter[E] {
+	return &closeCounter[E]{Iterator: SliceIterator(slc)}
+}
+
+func TestCombinators(t *testing.T) {
+	src := counted(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	actual := drain(Map(Filter(Skip(Take[int](src, 8), 2), func(v int) bool { return v%2 == 0 }),
+		func(v int) int { return v * v }))
+	if expected := []int{4, 16, 36}; !reflect.DeepEqual(expected, actual) || src.closed != 1 {
+		t.Fatalf("case: map filter skip take, expected: %v, actual: %v, closed: %v\n", expected, actual, src.closed)
+	}
+
+	a, b, c := counted(0, 1), counted[int](), counted(2, 3)
+	chain := Chain[int](a, b, c)
+	chain.MoveNext()
+	chain.MoveNext()
+	chain.MoveNext()
+	chain.Close()
+	if a.closed != 1 || b.closed != 1 || c.closed != 1 {
+		t.Fatalf("case: chain closed, actual: %v, %v, %v\n", a.closed, b.closed, c.closed)
+	}
+	if actual = drain(Chain[int](counted(0, 1), counted[int](), counted(2, 3))); !reflect.DeepEqual([]int{0, 1, 2, 3}, actual) {
+		t.Fatalf("case: chain, actual: %v\n", actual)
+	}
+
+	zipped := drain(Zip[int, string](counted(0, 1, 2), counted("a", "b")))
+	if expected := []Pair[int, string]{{0, "a"}, {1, "b"}}; !reflect.DeepEqual(expected, zipped) {
+		t.Fatalf("case: zip, expected: %v, actual: %v\n", expected, zipped)
+	}
+
+	enumerated := drain(Enumerate[string](counted("a", "b")))
+	if expected := []Pair[int, string]{{0, "a"}, {1, "b"}}; !reflect.DeepEqual(expected, enumerated) {
+		t.Fatalf("case: enumerate, expected: %v, actual: %v\n", expected, enumerated)
+	}
+
+	inners := []*closeCounter[int]{counted(0, 1), counted[int](), counted(2), counted(3, 4)}
+	outer := counted[Iterator[int]](inners[0], inners[1], inners[2], inners[3])
+	flat := Flatten[int](outer)
+	for i := 0; i < 4; i++ {
+		flat.MoveNext()
+	}
+	if flat.Current() != 3 {
+		t.Fatalf("case: flatten, expected: %v, actual: %v\n", 3, flat.Current())
+	}
+	flat.Close()
+	for i, inner := range inners {
+		if inner.closed != 1 {
+			t.Fatalf("case: flatten closed #%d, actual: %v\n", i, inner.closed)
+		}
+	}
+	if outer.closed != 1 {
+		t.Fatalf("case: flatten outer closed, actual: %v\n", outer.closed)
+	}
+
+	if actual = drain(Dedup[int](counted(0, 0, 1, 1, 1, 0, 2, 2))); !reflect.DeepEqual([]int{0, 1, 0, 2}, actual) {
+		t.Fatalf("case: dedup, actual: %v\n", actual)
+	}
+
+	var inspected []int
+	drain(Take(Inspect[int](counted(0, 1, 2, 3), func(v int) { inspected = append(inspected, v) }), 2))
+	if !reflect.DeepEqual([]int{0, 1}, inspected) {
+		t.Fatalf("case: inspect, actual: %v\n", inspected)
+	}
+}
@@ -37,3 +37,18 @@ func (e EmptyIterator[E]) Current() E {
 }
 
 func (e EmptyIterator[E]) Close() {}
+
+// ErrIterator is implemented by Iterators, which may stop early due to an error, e.g. decoding or reading.
+type ErrIterator[E any] interface {
+	Iterator[E]
+	// Err returns the error stopping the Iterator, or nil if it's not stopped by an error.
+	Err() error
+}
+
+// Err returns the error stopping iter if iter is an ErrIterator, and nil otherwise.
+func Err[E any](iter Iterator[E]) error {
+	if e, ok := iter.(ErrIterator[E]); ok {
+		return e.Err()
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package iterator
+
+// Peekable is an Iterator looking ahead of the current element, e.g. for tokenizers and parsers.
+type Peekable[E any] interface {
+	Iterator[E]
+	// Peek returns the next element without moving to it, and false if there's none.
+	Peek() (E, bool)
+	// PeekN returns up to n next elements without moving to them, fewer only if iter has fewer left, or nil if n <= 0.
+	PeekN(n int) []E
+	// PushBack pushes v back, so that the next MoveNext moves to v. It's usually the current element,
+	// but can be any value.
+	PushBack(v E)
+}
+
+// NewPeekable returns a Peekable wrapping iter, which only buffers the elements peeked or pushed back.
+// If iter is a Peekable itself, it's returned as it is.
+func NewPeekable[E any](iter Iterator[E]) Peekable[E] {
+	if p, ok := iter.(Peekable[E]); ok {
+		return p
+	}
+	return &peekable[E]{inner: iter}
+}
+
+type peekable[E any] struct {
+	inner Iterator[E]
+	buf   []E // buf[head:] are the next elements in order.
+	head  int
+	curr  E
+}
+
+// fill buffers up to n next elements, and returns the count of them.
+func (p *peekable[E]) fill(n int) int {
+	for len(p.buf)-p.head < n && p.inner.MoveNext() {
+		p.buf = append(p.buf, p.inner.Current())
+	}
+	if left := len(p.buf) - p.head; left < n {
+		return left
+	}
+	return n
+}
+
+func (p *peekable[E]) MoveNext() bool {
+	if p.fill(1) == 0 {
+		return false
+	}
+	var zero E
+	p.curr = p.buf[p.head]
+	p.buf[p.head] = zero
+	p.head++
+	if p.head == len(p.buf) {
+		p.buf, p.head = p.buf[:0], 0
+	}
+	return true
+}
+
+func (p *peekable[E]) Current() E {
+	return p.curr
+}
+
+func (p *peekable[E]) Peek() (v E, ok bool) {
+	if p.fill(1) == 0 {
+		return v, false
+	}
+	return p.buf[p.head], true
+}
+
+func (p *peekable[E]) PeekN(n int) []E {
+	if n <= 0 {
+		return nil
+	}
+	n = p.fill(n)
+	return append([]E(nil), p.buf[p.head:p.head+n]...)
+}
+
+func (p *peekable[E]) PushBack(v E) {
+	if p.head > 0 {
+		p.head--
+		p.buf[p.head] = v
+		return
+	}
+	p.buf = append(p.buf, v)
+	copy(p.buf[1:], p.buf)
+	p.buf[0] = v
+}
+
+func (p *peekable[E]) Close() {
+	p.inner.Close()
+}
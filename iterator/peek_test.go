@@ -0,0 +1,50 @@
+package iterator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPeekable(t *testing.T) {
+	src := counted(0, 1, 2, 3, 4)
+	p := NewPeekable[int](src)
+	if v, ok := p.Peek(); v != 0 || !ok {
+		t.Fatalf("case: peek, expected: %v, actual: %v, %v\n", 0, v, ok)
+	}
+	if p.PeekN(0) != nil || p.PeekN(-1) != nil {
+		t.Fatalf("case: non-positive n\n")
+	}
+	if vs := p.PeekN(3); !reflect.DeepEqual([]int{0, 1, 2}, vs) {
+		t.Fatalf("case: peekN, actual: %v\n", vs)
+	}
+	p.MoveNext()
+	p.MoveNext()
+	if p.Current() != 1 {
+		t.Fatalf("case: current, expected: %v, actual: %v\n", 1, p.Current())
+	}
+	p.PushBack(p.Current())
+	p.PushBack(-1)
+	if vs := p.PeekN(10); !reflect.DeepEqual([]int{-1, 1, 2, 3, 4}, vs) {
+		t.Fatalf("case: pushed back, actual: %v\n", vs)
+	}
+	if actual := drain[int](p); !reflect.DeepEqual([]int{-1, 1, 2, 3, 4}, actual) || src.closed != 1 {
+		t.Fatalf("case: drained, actual: %v, closed: %v\n", actual, src.closed)
+	}
+	if _, ok := p.Peek(); ok || len(p.PeekN(2)) != 0 {
+		t.Fatalf("case: exhausted\n")
+	}
+	if NewPeekable[int](p) != p {
+		t.Fatalf("case: rewrapped\n")
+	}
+}
+
+func TestRuneIteratorOffset(t *testing.T) {
+	iter := StringRuneIterator("a我b")
+	var offsets []int
+	for iter.MoveNext() {
+		offsets = append(offsets, iter.Offset())
+	}
+	if expected := []int{0, 1, 4}; !reflect.DeepEqual(expected, offsets) {
+		t.Fatalf("expected: %v, actual: %v\n", expected, offsets)
+	}
+}
@@ -0,0 +1,87 @@
+package iterator
+
+import "sync"
+
+// Prefetch returns an Iterator moving iter ahead of the consumer on its own goroutine, buffering up to n
+// elements, so that an I/O-bound iter overlaps with the consumer. iter is closed on the goroutine once it's
+// exhausted or the returned Iterator is closed, and a panic of iter is re-raised to the consumer, while the
+// error stopping iter is returned by its Err.
+func Prefetch[E any](iter Iterator[E], n int) Iterator[E] {
+	p := &prefetchIterator[E]{
+		ch:   make(chan E, n),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go p.fetch(iter)
+	return p
+}
+
+type prefetchIterator[E any] struct {
+	ch       chan E
+	stop     chan struct{}
+	done     chan struct{} // closed when the goroutine finishes, after iter is closed.
+	stopOnce sync.Once
+	curr     E
+	closed   bool
+	panicked any   // written before done is closed.
+	err      error // the error stopping iter, written before done is closed.
+}
+
+func (p *prefetchIterator[E]) fetch(iter Iterator[E]) {
+	defer close(p.done)
+	defer func() {
+		p.panicked = recover()
+		close(p.ch)
+	}()
+	defer iter.Close()
+	for iter.MoveNext() {
+		select {
+		case p.ch <- iter.Current():
+		case <-p.stop:
+			return
+		}
+	}
+	p.err = Err(iter)
+}
+
+func (p *prefetchIterator[E]) MoveNext() bool {
+	if p.closed {
+		return false
+	}
+	v, ok := <-p.ch
+	if !ok {
+		p.wait()
+		return false
+	}
+	p.curr = v
+	return true
+}
+
+func (p *prefetchIterator[E]) Current() E {
+	return p.curr
+}
+
+func (p *prefetchIterator[E]) Close() {
+	p.closed = true
+	p.stopOnce.Do(func() { close(p.stop) })
+	p.wait()
+}
+
+// Err returns the error stopping iter once the Iterator is exhausted, if iter is an ErrIterator.
+func (p *prefetchIterator[E]) Err() error {
+	select {
+	case <-p.done:
+		return p.err
+	default:
+		return nil
+	}
+}
+
+// wait waits for the goroutine to finish, and re-raises its panic if any.
+func (p *prefetchIterator[E]) wait() {
+	<-p.done
+	if r := p.panicked; r != nil {
+		p.panicked = nil
+		panic(r)
+	}
+}
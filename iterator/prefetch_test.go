@@ -0,0 +1,60 @@
+package iterator
+
+import (
+	"reflect"
+	"testing"
+)
+
+type closeCounter[E any] struct {
+	Iterator[E]
+	closed int
+}
+
+func (c *closeCounter[E]) Close() {
+	c.closed++
+	c.Iterator.Close()
+}
+
+func TestPrefetch(t *testing.T) {
+	src := &closeCounter[int]{Iterator: SliceIterator([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})}
+	iter := Prefetch[int](src, 3)
+	var actual []int
+	for iter.MoveNext() {
+		actual = append(actual, iter.Current())
+	}
+	iter.Close()
+	if expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}; !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: exhausted, expected: %v, actual: %v\n", expected, actual)
+	}
+	if src.closed != 1 {
+		t.Fatalf("case: exhausted closed, expected: 1, actual: %v\n", src.closed)
+	}
+
+	src = &closeCounter[int]{Iterator: SliceIterator(make([]int, 1000))}
+	iter = Prefetch[int](src, 0)
+	iter.MoveNext()
+	iter.Close()
+	iter.Close()
+	if iter.MoveNext() || src.closed != 1 {
+		t.Fatalf("case: closed early, actual: %v\n", src.closed)
+	}
+}
+
+type panicIterator struct {
+	EmptyIterator[int]
+}
+
+func (panicIterator) MoveNext() bool {
+	panic("move next")
+}
+
+func TestPrefetchPanic(t *testing.T) {
+	iter := Prefetch[int](panicIterator{}, 1)
+	defer func() {
+		if r := recover(); r != "move next" {
+			t.Fatalf("case: re-raised, actual: %v\n", r)
+		}
+	}()
+	iter.MoveNext()
+	t.Fatalf("case: re-raised, expected panic\n")
+}
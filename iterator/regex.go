@@ -0,0 +1,457 @@
+package iterator
+
+import (
+	"io"
+	"regexp"
+	"regexp/syntax"
+	"unicode/utf8"
+)
+
+// Match is a match of a regexp.
+type Match struct {
+	// Index holds the pairs of byte offsets of the whole match and its submatches in the input, like
+	// regexp.Regexp.FindSubmatchIndex, where -1 marks submatches not participating in the match.
+	Index []int
+	// Groups holds the texts of the whole match and its submatches, which are empty if not participating.
+	Groups []string
+}
+
+// Text returns the text of the whole match.
+func (m Match) Text() string {
+	return m.Groups[0]
+}
+
+// RegexMatches returns an Iterable of successive non-overlapping matches of re in input, like
+// regexp.Regexp.FindAllSubmatchIndex, but searching for one match at a time as they're moved to, so that stopping
+// early skips searching the rest. Regexps compiled by CompilePOSIX or made Longest are not supported.
+func RegexMatches[S string | []byte](re *regexp.Regexp, input S) Iterable[Match] {
+	return regexMatchIterable{newInput: newRegexInput(newRegexSearcher(re), input), size: uint64(len(input)) + 1}
+}
+
+// RegexSplit returns an Iterable of substrings of input between matches of re, like regexp.Regexp.Split with
+// n < 0, but searching for one match at a time as they're moved to.
+func RegexSplit[S string | []byte](re *regexp.Regexp, input S) Iterable[string] {
+	return regexSplitIterable{
+		newInput: newRegexInput(newRegexSearcher(re), input), size: uint64(len(input)) + 1, expr: re.String(),
+	}
+}
+
+// RegexReaderMatches returns an Iterable of matches of re in rr like RegexMatches, whose offsets and texts are of
+// the runes read encoded in utf-8, except that utf8.RuneError of size 1 is the byte 0xff, so that the offsets are
+// those of the bytes read by utf-8 RuneReaders like bufio.Reader. Its Iterators read rr from where it is, and buffer the bytes
+// read since the end of the last match only, which are all the rest of rr in the worst case of no more matches.
+// They are ErrIterators, which stop with the error of rr other than io.EOF.
+func RegexReaderMatches(re *regexp.Regexp, rr io.RuneReader) Iterable[Match] {
+	return regexMatchIterable{newInput: newReaderInput(newRegexSearcher(re), rr)}
+}
+
+// RegexReaderSplit returns an Iterable of substrings of rr between matches of re like RegexSplit. Its Iterators
+// read and buffer rr like those of RegexReaderMatches, where the bytes buffered are to be the next substring.
+func RegexReaderSplit(re *regexp.Regexp, rr io.RuneReader) Iterable[string] {
+	return regexSplitIterable{newInput: newReaderInput(newRegexSearcher(re), rr), expr: re.String()}
+}
+
+// region regexSearcher
+
+// regexSearcher searches for the first match of a regexp at or after a position of the input, since regexp
+// searches from the start of the input only.
+type regexSearcher struct {
+	re *regexp.Regexp
+	// ctx matches a rune right before the position and then the first match of re, which sees the rune as its
+	// context, or is nil if re has no assertions depending on the context, so that the input is just sliced.
+	ctx *regexp.Regexp
+}
+
+func newRegexSearcher(re *regexp.Regexp) regexSearcher {
+	s := regexSearcher{re: re}
+	if parsed, err := syntax.Parse(re.String(), syntax.Perl); err != nil || dependsOnContext(parsed) {
+		s.ctx = regexp.MustCompile(`^(?s:.)(?s:.*?)(` + re.String() + `)`)
+	}
+	return s
+}
+
+// dependsOnContext reports whether re has assertions, which depend on the rune before.
+func dependsOnContext(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpBeginLine, syntax.OpBeginText, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return true
+	}
+	for _, sub := range re.Sub {
+		if dependsOnContext(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// offsetIndex adds base to the non-negative offsets of idx, which is the result of ctx if withCtx is true.
+func offsetIndex(idx []int, base int, withCtx bool) []int {
+	if idx == nil {
+		return nil
+	}
+	if withCtx {
+		idx = idx[2:]
+	}
+	for i := range idx {
+		if idx[i] >= 0 {
+			idx[i] += base
+		}
+	}
+	return idx
+}
+
+// endregion
+
+// region regexInput
+
+// regexInput is the input of regexp matching.
+type regexInput interface {
+	// find returns the indexes of the first match at or after pos, or nil if there is none.
+	find(pos int) []int
+	// width returns the byte width of the rune at pos, which is 0 at the end.
+	width(pos int) int
+	// text returns the text between the offsets i and j.
+	text(i, j int) string
+	// end returns the offset of the end.
+	end() int
+	// discard lets go of the input before keep, except the rune right before it.
+	discard(keep int)
+	// err returns the error reading the input.
+	err() error
+}
+
+func newRegexInput[S string | []byte](s regexSearcher, input S) func() regexInput {
+	switch in := any(input).(type) {
+	case string:
+		return func() regexInput { return stringInput{s, in} }
+	case []byte:
+		return func() regexInput { return bytesInput{s, in} }
+	}
+	panic("unreachable")
+}
+
+type stringInput struct {
+	regexSearcher
+	str string
+}
+
+func (in stringInput) find(pos int) []int {
+	if pos == 0 || in.ctx == nil {
+		return offsetIndex(in.re.FindStringSubmatchIndex(in.str[pos:]), pos, false)
+	}
+	_, w := utf8.DecodeLastRuneInString(in.str[:pos])
+	return offsetIndex(in.ctx.FindStringSubmatchIndex(in.str[pos-w:]), pos-w, true)
+}
+
+func (in stringInput) width(pos int) int {
+	_, w := utf8.DecodeRuneInString(in.str[pos:])
+	return w
+}
+
+func (in stringInput) text(i, j int) string {
+	return in.str[i:j]
+}
+
+func (in stringInput) end() int {
+	return len(in.str)
+}
+
+func (in stringInput) discard(int) {}
+
+func (in stringInput) err() error {
+	return nil
+}
+
+type bytesInput struct {
+	regexSearcher
+	bytes []byte
+}
+
+func (in bytesInput) find(pos int) []int {
+	if pos == 0 || in.ctx == nil {
+		return offsetIndex(in.re.FindSubmatchIndex(in.bytes[pos:]), pos, false)
+	}
+	_, w := utf8.DecodeLastRune(in.bytes[:pos])
+	return offsetIndex(in.ctx.FindSubmatchIndex(in.bytes[pos-w:]), pos-w, true)
+}
+
+func (in bytesInput) width(pos int) int {
+	_, w := utf8.DecodeRune(in.bytes[pos:])
+	return w
+}
+
+func (in bytesInput) text(i, j int) string {
+	return string(in.bytes[i:j])
+}
+
+func (in bytesInput) end() int {
+	return len(in.bytes)
+}
+
+func (in bytesInput) discard(int) {}
+
+func (in bytesInput) err() error {
+	return nil
+}
+
+// readerInput buffers the bytes of the runes read from an io.RuneReader, which are replayed to searches from
+// a position. Runes are buffered as utf-8, except that utf8.RuneError of size 1 is buffered as the byte 0xff,
+// which decodes back to the same rune and size.
+type readerInput struct {
+	regexSearcher
+	rr      io.RuneReader
+	buf     []byte
+	base    int // offset of buf[0].
+	eof     bool
+	readErr error
+}
+
+func newReaderInput(s regexSearcher, rr io.RuneReader) func() regexInput {
+	return func() regexInput { return &readerInput{regexSearcher: s, rr: rr} }
+}
+
+// pull reads the next rune, and returns false at the end.
+func (in *readerInput) pull() bool {
+	if in.eof {
+		return false
+	}
+	r, size, err := in.rr.ReadRune()
+	if err != nil {
+		in.eof = true
+		if err != io.EOF {
+			in.readErr = err
+		}
+		return false
+	}
+	if r == utf8.RuneError && size == 1 {
+		in.buf = append(in.buf, 0xff)
+	} else {
+		in.buf = utf8.AppendRune(in.buf, r)
+	}
+	return true
+}
+
+// fill reads runes until the rune at pos is buffered, or until the end.
+func (in *readerInput) fill(pos int) {
+	for pos >= in.base+len(in.buf) && in.pull() {
+	}
+}
+
+func (in *readerInput) find(pos int) []int {
+	in.fill(pos)
+	if pos == 0 || in.ctx == nil {
+		return offsetIndex(in.re.FindReaderSubmatchIndex(&replayReader{in: in, off: pos}), pos, false)
+	}
+	_, w := utf8.DecodeLastRune(in.buf[:pos-in.base])
+	return offsetIndex(in.ctx.FindReaderSubmatchIndex(&replayReader{in: in, off: pos - w}), pos-w, true)
+}
+
+func (in *readerInput) width(pos int) int {
+	in.fill(pos)
+	if i := pos - in.base; i < len(in.buf) {
+		_, w := utf8.DecodeRune(in.buf[i:])
+		return w
+	}
+	return 0
+}
+
+func (in *readerInput) text(i, j int) string {
+	in.fill(j - 1)
+	return string(in.buf[i-in.base : j-in.base])
+}
+
+func (in *readerInput) end() int {
+	for in.pull() {
+	}
+	return in.base + len(in.buf)
+}
+
+func (in *readerInput) discard(keep int) {
+	in.fill(keep)
+	n := keep - in.base
+	if n <= 0 {
+		return
+	}
+	_, w := utf8.DecodeLastRune(in.buf[:n])
+	n -= w
+	// moves the rest to the front, so that the capacity only grows with the bytes kept at once.
+	in.buf = in.buf[:copy(in.buf, in.buf[n:])]
+	in.base += n
+}
+
+func (in *readerInput) err() error {
+	return in.readErr
+}
+
+// replayReader reads buffered runes from the offset off, and then those read from the underlying io.RuneReader.
+type replayReader struct {
+	in  *readerInput
+	off int
+}
+
+func (r *replayReader) ReadRune() (rune, int, error) {
+	i := r.off - r.in.base
+	if i == len(r.in.buf) && !r.in.pull() {
+		return 0, 0, io.EOF
+	}
+	rn, size := utf8.DecodeRune(r.in.buf[i:])
+	r.off += size
+	return rn, size, nil
+}
+
+// endregion
+
+// region regexMatcher
+
+// regexMatcher finds successive non-overlapping matches, where an empty match right after the previous match
+// is ignored, as regexp does.
+type regexMatcher struct {
+	in      regexInput
+	pos     int
+	prevEnd int
+	done    bool
+}
+
+func (m *regexMatcher) next() []int {
+	for !m.done {
+		idx := m.in.find(m.pos)
+		if idx == nil {
+			m.done = true
+			return nil
+		}
+		accept := true
+		if idx[1] == m.pos {
+			// an empty match, so moves on to the next rune.
+			accept = idx[0] != m.prevEnd
+			if w := m.in.width(m.pos); w > 0 {
+				m.pos += w
+			} else {
+				m.done = true
+			}
+		} else {
+			m.pos = idx[1]
+		}
+		m.prevEnd = idx[1]
+		if accept {
+			return idx
+		}
+	}
+	return nil
+}
+
+// endregion
+
+// region regexMatchIterable
+
+type regexMatchIterable struct {
+	newInput func() regexInput
+	size     uint64
+}
+
+func (r regexMatchIterable) Iterator() Iterator[Match] {
+	return &regexMatchIterator{regexMatcher: regexMatcher{in: r.newInput(), prevEnd: -1}}
+}
+
+// Size returns the upper bound of the count of matches, which is unknown for io.RuneReader.
+func (r regexMatchIterable) Size() (n uint64, known bool) {
+	return r.size, false
+}
+
+type regexMatchIterator struct {
+	regexMatcher
+	curr Match
+}
+
+func (r *regexMatchIterator) MoveNext() bool {
+	idx := r.next()
+	if idx == nil {
+		return false
+	}
+	groups := make([]string, len(idx)/2)
+	for i := range groups {
+		if idx[2*i] >= 0 {
+			groups[i] = r.in.text(idx[2*i], idx[2*i+1])
+		}
+	}
+	r.curr = Match{Index: idx, Groups: groups}
+	r.in.discard(r.pos)
+	return true
+}
+
+func (r *regexMatchIterator) Current() Match {
+	return r.curr
+}
+
+func (r *regexMatchIterator) Close() {
+	r.done = true
+}
+
+func (r *regexMatchIterator) Err() error {
+	return r.in.err()
+}
+
+// endregion
+
+// region regexSplitIterable
+
+type regexSplitIterable struct {
+	newInput func() regexInput
+	size     uint64
+	expr     string
+}
+
+func (r regexSplitIterable) Iterator() Iterator[string] {
+	return &regexSplitIterator{regexMatcher: regexMatcher{in: r.newInput(), prevEnd: -1}, expr: r.expr}
+}
+
+// Size returns the upper bound of the count of substrings, which is unknown for io.RuneReader.
+func (r regexSplitIterable) Size() (n uint64, known bool) {
+	return r.size, false
+}
+
+type regexSplitIterator struct {
+	regexMatcher
+	expr      string
+	beg       int // offset of the next substring.
+	lastStart int // offset of the last match.
+	finished  bool
+	curr      string
+}
+
+func (r *regexSplitIterator) MoveNext() bool {
+	for !r.finished {
+		idx := r.next()
+		if idx == nil {
+			r.finished = true
+			// the rest after the last match, or an empty string for an empty input as regexp does.
+			if end := r.in.end(); end == 0 && r.expr != "" || r.lastStart != end {
+				r.curr = r.in.text(r.beg, end)
+				return true
+			}
+			return false
+		}
+		r.lastStart = idx[0]
+		beg := r.beg
+		r.beg = idx[1]
+		if idx[1] != 0 {
+			r.curr = r.in.text(beg, idx[0])
+			r.in.discard(r.beg)
+			return true
+		}
+	}
+	return false
+}
+
+func (r *regexSplitIterator) Current() string {
+	return r.curr
+}
+
+func (r *regexSplitIterator) Close() {
+	r.done, r.finished = true, true
+}
+
+func (r *regexSplitIterator) Err() error {
+	return r.in.err()
+}
+
+// endregion
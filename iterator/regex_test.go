@@ -0,0 +1,142 @@
+package iterator
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var regexCases = []struct {
+	expr  string
+	input string
+}{
+	{`a+`, "baaab aa"},
+	{`(\w+)@(\w+)?`, "x@y, z@ w@v"},
+	{`^a`, "aaa"},
+	{`(?m)^(\w)`, "ab\ncd\n\nef"},
+	{`\b\w`, "foo bar,baz"},
+	{`\B.`, "foo bar"},
+	{`x*`, "axxbx"},
+	{``, "abc"},
+	{`$`, "ab"},
+	{`,`, "a,b,,c,"},
+	{`\s*`, " a b  "},
+	{`我`, "你我他我"},
+	{`.`, "a\xff我"},
+	{`a|`, ""},
+	{``, ""},
+}
+
+func collectMatches(t *testing.T, iterable Iterable[Match]) (idx [][]int, texts [][]string) {
+	iter := iterable.Iterator()
+	defer iter.Close()
+	for iter.MoveNext() {
+		idx = append(idx, iter.Current().Index)
+		texts = append(texts, iter.Current().Groups)
+	}
+	if err := Err(iter); err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	return
+}
+
+func TestRegexMatches(t *testing.T) {
+	for _, c := range regexCases {
+		re := regexp.MustCompile(c.expr)
+		expectedIdx := re.FindAllStringSubmatchIndex(c.input, -1)
+		var expectedTexts [][]string
+		for _, m := range re.FindAllStringSubmatch(c.input, -1) {
+			expectedTexts = append(expectedTexts, m)
+		}
+		for name, iterable := range map[string]Iterable[Match]{
+			"string": RegexMatches(re, c.input),
+			"bytes":  RegexMatches(re, []byte(c.input)),
+			"reader": RegexReaderMatches(re, strings.NewReader(c.input)),
+		} {
+			idx, texts := collectMatches(t, iterable)
+			if !reflect.DeepEqual(expectedIdx, idx) || !reflect.DeepEqual(expectedTexts, texts) {
+				t.Fatalf("case: %v %q %q, expected: %v %q, actual: %v %q\n",
+					name, c.expr, c.input, expectedIdx, expectedTexts, idx, texts)
+			}
+		}
+	}
+}
+
+func TestRegexSplit(t *testing.T) {
+	for _, c := range regexCases {
+		re := regexp.MustCompile(c.expr)
+		expected := re.Split(c.input, -1)
+		for name, iterable := range map[string]Iterable[string]{
+			"string": RegexSplit(re, c.input),
+			"bytes":  RegexSplit(re, []byte(c.input)),
+			"reader": RegexReaderSplit(re, strings.NewReader(c.input)),
+		} {
+			var actual []string
+			iter := iterable.Iterator()
+			for iter.MoveNext() {
+				actual = append(actual, iter.Current())
+			}
+			iter.Close()
+			if len(expected) != 0 || len(actual) != 0 {
+				if !reflect.DeepEqual(expected, actual) {
+					t.Fatalf("case: %v %q %q, expected: %q, actual: %q\n", name, c.expr, c.input, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+// countingRuneReader counts runes read, and fails with err at the end if err is not nil.
+type countingRuneReader struct {
+	*strings.Reader
+	read int
+	err  error
+}
+
+func (c *countingRuneReader) ReadRune() (rune, int, error) {
+	r, size, err := c.Reader.ReadRune()
+	if err == nil {
+		c.read++
+	} else if c.err != nil {
+		err = c.err
+	}
+	return r, size, err
+}
+
+func TestRegexReaderLazy(t *testing.T) {
+	rr := &countingRuneReader{Reader: strings.NewReader("id=1 id=2 " + strings.Repeat("x", 1000))}
+	iter := RegexReaderMatches(regexp.MustCompile(`id=(\d)`), rr).Iterator()
+	if !iter.MoveNext() || iter.Current().Groups[1] != "1" || iter.Current().Text() != "id=1" {
+		t.Fatalf("case: first, actual: %v\n", iter.Current())
+	}
+	iter.Close()
+	if rr.read > 10 {
+		t.Fatalf("case: lazy, read: %v\n", rr.read)
+	}
+
+	// dense matches keep the buffer bounded by the bytes kept between matches.
+	input := strings.Repeat("ab我,", 100000)
+	iter = RegexReaderMatches(regexp.MustCompile(`\w+`), strings.NewReader(input)).Iterator()
+	in := iter.(*regexMatchIterator).in.(*readerInput)
+	var matched, maxCap int
+	for iter.MoveNext() {
+		matched++
+		if cap(in.buf) > maxCap {
+			maxCap = cap(in.buf)
+		}
+	}
+	if matched != 100000 || maxCap > 64 {
+		t.Fatalf("case: bounded, matched: %v, max capacity: %v\n", matched, maxCap)
+	}
+
+	broken := errors.New("broken")
+	rr = &countingRuneReader{Reader: strings.NewReader("a,b"), err: broken}
+	iter = RegexReaderMatches(regexp.MustCompile(`\w`), rr).Iterator()
+	for iter.MoveNext() {
+	}
+	if err := Err(iter); err != broken {
+		t.Fatalf("case: error, actual: %v\n", err)
+	}
+}
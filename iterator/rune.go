@@ -1,8 +1,10 @@
 package iterator
 
 import (
+	"errors"
+	"fmt"
+	"sync"
 	"unicode/utf8"
-	"unsafe"
 )
 
 type BytesRuneIterable []byte
@@ -11,22 +13,71 @@ func (b BytesRuneIterable) Iterator() Iterator[rune] {
 	return BytesRuneIterator(b)
 }
 
+// Size returns the count of bytes, which is the upper bound of the count of runes.
 func (b BytesRuneIterable) Size() (n uint64, known bool) {
 	return uint64(len(b)), false
 }
 
+// WithOptions returns an Iterable of runes of b decoded by opts.
+func (b BytesRuneIterable) WithOptions(opts RuneOptions) Iterable[rune] {
+	return &optionedRuneIterable{data: b, opts: opts}
+}
+
+// StringRuneIterable returns an Iterable of runes of str, which decodes str in place without copying.
 func StringRuneIterable(str string) Iterable[rune] {
-	return BytesRuneIterable(*(*[]byte)(unsafe.Pointer(&str)))
+	return StringRuneIterableWithOptions(str, RuneOptions{})
+}
+
+// StringRuneIterableWithOptions is like StringRuneIterable, but decodes str by opts.
+func StringRuneIterableWithOptions(str string, opts RuneOptions) Iterable[rune] {
+	return &optionedRuneIterable{data: stringBytes(str), opts: opts}
+}
+
+// ErrInvalidUTF8 is wrapped by the error of rune Iterators stopping at malformed utf-8 under StopInvalid.
+var ErrInvalidUTF8 = errors.New("iterator: invalid utf-8")
+
+// InvalidUTF8 decides how rune Iterators handle malformed utf-8.
+type InvalidUTF8 uint8
+
+const (
+	// ReplaceInvalid yields utf8.RuneError for each malformed byte, which is the default.
+	ReplaceInvalid InvalidUTF8 = iota
+	// SkipInvalid skips malformed bytes silently.
+	SkipInvalid
+	// StopInvalid stops at the first malformed byte with an error wrapping ErrInvalidUTF8, which tells its offset.
+	StopInvalid
+)
+
+// RuneOptions are options of rune Iterables decoding utf-8.
+type RuneOptions struct {
+	// Invalid decides how malformed utf-8 is handled.
+	Invalid InvalidUTF8
+	// ExactSize makes Size count the runes to yield exactly, e.g. for preallocation downstream, instead of returning
+	// the count of bytes as the upper bound. It decodes all bytes once on the first call of Size.
+	ExactSize bool
+}
+
+// RuneIterator is an Iterator[rune] decoding utf-8, which also tells where the current rune is.
+type RuneIterator interface {
+	BidiIterator[rune]
+	// Offset returns the byte offset of the current rune, e.g. for error reporting, which is the length of the
+	// bytes once exhausted, or the offset of the malformed byte once stopped under StopInvalid.
+	Offset() int
+	// Err returns the error stopping the Iterator under StopInvalid.
+	Err() error
 }
 
 type runeIterator struct {
 	EmptyIterator[rune]
-	data []byte
-	idx  int
-	curr rune
+	data    []byte
+	idx     int // offset right after the current rune.
+	off     int // offset of the current rune.
+	curr    rune
+	invalid InvalidUTF8
+	err     error
 }
 
-func BytesRuneIterator(bytes []byte) Iterator[rune] {
+func BytesRuneIterator(bytes []byte) RuneIterator {
 	return &runeIterator{
 		data: bytes,
 		idx:  0,
@@ -34,24 +85,115 @@ func BytesRuneIterator(bytes []byte) Iterator[rune] {
 	}
 }
 
-func StringRuneIterator(str string) Iterator[rune] {
+func StringRuneIterator(str string) RuneIterator {
 	return &runeIterator{
-		data: *(*[]byte)(unsafe.Pointer(&str)),
+		data: stringBytes(str),
 		idx:  0,
 		curr: utf8.RuneError,
 	}
 }
 
+// invalidAt reports whether the decoded rn of size is a malformed byte, which is not replaced under the policy.
+func (r *runeIterator) invalidAt(rn rune, size int) bool {
+	return rn == utf8.RuneError && size == 1 && r.invalid != ReplaceInvalid
+}
+
+func (r *runeIterator) stop(off int) {
+	r.off = off
+	r.err = fmt.Errorf("%w at byte offset %d", ErrInvalidUTF8, off)
+}
+
 func (r *runeIterator) MoveNext() bool {
-	if r.idx >= len(r.data) {
+	if r.err != nil {
 		return false
 	}
-	rn, size := utf8.DecodeRune(r.data[r.idx:])
-	r.curr = rn
-	r.idx += size
-	return true
+	for r.idx < len(r.data) {
+		rn, size := utf8.DecodeRune(r.data[r.idx:])
+		if r.invalidAt(rn, size) {
+			if r.invalid == StopInvalid {
+				r.stop(r.idx)
+				return false
+			}
+			r.idx++
+			continue
+		}
+		r.curr = rn
+		r.off = r.idx
+		r.idx += size
+		return true
+	}
+	r.off = len(r.data)
+	return false
+}
+
+func (r *runeIterator) MovePrev() bool {
+	if r.idx == 0 || r.err != nil {
+		return false
+	}
+	for r.off > 0 {
+		rn, size := utf8.DecodeLastRune(r.data[:r.off])
+		if r.invalidAt(rn, size) {
+			if r.invalid == StopInvalid {
+				r.stop(r.off - 1)
+				return false
+			}
+			r.off--
+			continue
+		}
+		r.curr = rn
+		r.idx = r.off
+		r.off -= size
+		return true
+	}
+	r.idx = 0
+	return false
+}
+
+func (r *runeIterator) Err() error {
+	return r.err
+}
+
+func (r *runeIterator) Offset() int {
+	return r.off
 }
 
 func (r *runeIterator) Current() rune {
 	return r.curr
 }
+
+// region optionedRuneIterable
+
+// optionedRuneIterable is an Iterable of runes of bytes decoded by opts, whose bytes may be of a string.
+type optionedRuneIterable struct {
+	data  []byte
+	opts  RuneOptions
+	once  sync.Once
+	count uint64
+}
+
+func (o *optionedRuneIterable) Iterator() Iterator[rune] {
+	return &runeIterator{
+		data:    o.data,
+		curr:    utf8.RuneError,
+		invalid: o.opts.Invalid,
+	}
+}
+
+func (o *optionedRuneIterable) Size() (n uint64, known bool) {
+	if !o.opts.ExactSize {
+		return uint64(len(o.data)), false
+	}
+	o.once.Do(func() {
+		if o.opts.Invalid == ReplaceInvalid {
+			o.count = uint64(utf8.RuneCount(o.data))
+			return
+		}
+		iter := o.Iterator()
+		for iter.MoveNext() {
+			o.count++
+		}
+	})
+	return o.count, true
+}
+
+// endregion
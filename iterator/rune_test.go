@@ -1,7 +1,9 @@
 package iterator
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -12,3 +14,64 @@ func TestStringRuneIter(t *testing.T) {
 		fmt.Print(string(iter.Current()))
 	}
 }
+
+func TestRuneOptions(t *testing.T) {
+	data := []byte("a\xffb\xe6\x88c")
+	cases := []struct {
+		invalid InvalidUTF8
+		runes   string
+		offsets []int
+		err     bool
+	}{
+		{ReplaceInvalid, "a�b��c", []int{0, 1, 2, 3, 4, 5}, false},
+		{SkipInvalid, "abc", []int{0, 2, 5}, false},
+		{StopInvalid, "a", []int{0}, true},
+	}
+	for _, c := range cases {
+		iterable := BytesRuneIterable(data).WithOptions(RuneOptions{Invalid: c.invalid, ExactSize: true})
+		iter := iterable.Iterator().(RuneIterator)
+		var runes []rune
+		var offsets []int
+		for iter.MoveNext() {
+			runes = append(runes, iter.Current())
+			offsets = append(offsets, iter.Offset())
+		}
+		if string(runes) != c.runes || !reflect.DeepEqual(c.offsets, offsets) {
+			t.Fatalf("case: %v, expected: %q %v, actual: %q %v\n", c.invalid, c.runes, c.offsets, string(runes), offsets)
+		}
+		if err := Err[rune](iter); errors.Is(err, ErrInvalidUTF8) != c.err {
+			t.Fatalf("case: %v, unexpected error: %v\n", c.invalid, err)
+		}
+		if n, known := iterable.Size(); !known || n != uint64(len(runes)) {
+			t.Fatalf("case: %v size, expected: %v, actual: %v, %v\n", c.invalid, len(runes), n, known)
+		}
+	}
+
+	iter := StringRuneIterableWithOptions("\xffa\xff", RuneOptions{Invalid: SkipInvalid}).Iterator().(RuneIterator)
+	if !iter.MoveNext() || iter.Current() != 'a' || iter.MoveNext() || !iter.MovePrev() || iter.Current() != 'a' ||
+		iter.MovePrev() || !iter.MoveNext() || iter.Current() != 'a' {
+		t.Fatalf("case: skip backward\n")
+	}
+	iter = StringRuneIterableWithOptions("a\xffb", RuneOptions{Invalid: StopInvalid}).Iterator().(RuneIterator)
+	for iter.MoveNext() {
+	}
+	if iter.Offset() != 1 || iter.Err() == nil || iter.Err().Error() != "iterator: invalid utf-8 at byte offset 1" {
+		t.Fatalf("case: stop offset, actual: %v, %v\n", iter.Offset(), iter.Err())
+	}
+	if n, known := StringRuneIterableWithOptions("我", RuneOptions{}).Size(); n != 3 || known {
+		t.Fatalf("case: inexact size, actual: %v, %v\n", n, known)
+	}
+	for _, str := range []string{"", "a我"} {
+		iterable := StringRuneIterable(str)
+		if _, ok := iterable.(BytesRuneIterable); ok {
+			t.Fatalf("case: %q, exposed the bytes of the string\n", str)
+		}
+		var runes []rune
+		for iter := iterable.Iterator(); iter.MoveNext(); {
+			runes = append(runes, iter.Current())
+		}
+		if string(runes) != str {
+			t.Fatalf("case: %q, actual: %q\n", str, string(runes))
+		}
+	}
+}
@@ -0,0 +1,423 @@
+package iterator
+
+import "unicode/utf8"
+
+// GraphemeIterable returns an Iterable of the extended grapheme clusters of str, i.e. user-perceived characters,
+// following UAX #29. E.g. emoji ZWJ sequences, flags, letters with combining marks and Indic conjuncts are single
+// clusters. For the conjunct rule GB9c, all Extend and ZWJ runes other than viramas are taken as InCB=Extend.
+func GraphemeIterable(str string) Iterable[string] {
+	return segmentIterable{str: str, first: firstGrapheme}
+}
+
+// WordIterable returns an Iterable of the segments of str between word boundaries following UAX #29, which include
+// spaces and punctuation between words. Words of scripts like Thai, which need dictionaries, are not split.
+func WordIterable(str string) Iterable[string] {
+	return segmentIterable{str: str, first: firstWord}
+}
+
+// LineIterable returns an Iterable of the segments of str between line break opportunities following UAX #14,
+// e.g. for wrapping. Each segment keeps its trailing spaces, and its trailing line break if the break is mandatory.
+// Words of scripts like Thai, which need dictionaries, are not split.
+func LineIterable(str string) Iterable[string] {
+	return segmentIterable{str: str, first: firstLine}
+}
+
+// SegmentIterator is an Iterator[string] of segments of a string, which also tells where the current segment is.
+// Iterators of GraphemeIterable, WordIterable and LineIterable are all SegmentIterators.
+type SegmentIterator interface {
+	Iterator[string]
+	// Offset returns the byte offset of the current segment, which is the length of the string once exhausted.
+	Offset() int
+}
+
+// region segmentIterable
+
+type segmentIterable struct {
+	str string
+	// first returns the length of the first segment of the non-empty s.
+	first func(s string) int
+}
+
+func (s segmentIterable) Iterator() Iterator[string] {
+	return &segmentIterator{segmentIterable: s}
+}
+
+// Size returns the length of the string, which is the upper bound of the count of segments.
+func (s segmentIterable) Size() (n uint64, known bool) {
+	return uint64(len(s.str)), false
+}
+
+type segmentIterator struct {
+	segmentIterable
+	off, end int
+}
+
+func (s *segmentIterator) MoveNext() bool {
+	s.off = s.end
+	if s.end >= len(s.str) {
+		return false
+	}
+	s.end += s.first(s.str[s.end:])
+	return true
+}
+
+func (s *segmentIterator) Current() string {
+	return s.str[s.off:s.end]
+}
+
+func (s *segmentIterator) Close() {}
+
+func (s *segmentIterator) Offset() int {
+	return s.off
+}
+
+// endregion
+
+// region Grapheme
+
+func firstGrapheme(s string) int {
+	r, pos := utf8.DecodeRuneInString(s)
+	prev := graphemeProp(r)
+	// pict is whether the cluster so far ends with Extended_Pictographic Extend*, or with a ZWJ right after it.
+	pict := extPictographic.has(r)
+	ri := 0
+	if prev == gcbRI {
+		ri = 1
+	}
+	conj := conjNone.next(r, prev)
+	for pos < len(s) {
+		r, size := utf8.DecodeRuneInString(s[pos:])
+		curr := graphemeProp(r)
+		if graphemeBreak(prev, curr, pict && extPictographic.has(r), conj == conjLinked && incbConsonant.has(r), ri) {
+			break
+		}
+		switch {
+		case curr == gcbExtend || curr == gcbZWJ:
+			pict = pict && prev != gcbZWJ
+		default:
+			pict = extPictographic.has(r)
+		}
+		if curr == gcbRI {
+			ri++
+		}
+		conj = conj.next(r, curr)
+		prev = curr
+		pos += size
+	}
+	return pos
+}
+
+// conjunct is the state of matching an Indic conjunct for GB9c.
+type conjunct uint8
+
+const (
+	conjNone      conjunct = iota
+	conjConsonant          // the cluster so far ends with Consonant [Extend Linker]* without Linker.
+	conjLinked             // the cluster so far ends with Consonant [Extend Linker]* Linker [Extend Linker]*.
+)
+
+// next returns the state after r, whose Grapheme_Cluster_Break is prop.
+func (c conjunct) next(r rune, prop gcbProp) conjunct {
+	switch {
+	case incbConsonant.has(r):
+		return conjConsonant
+	case isIncbLinker(r):
+		if c == conjNone {
+			return conjNone
+		}
+		return conjLinked
+	case prop == gcbExtend || prop == gcbZWJ:
+		return c
+	}
+	return conjNone
+}
+
+// graphemeBreak reports whether there is a boundary between prev and curr, where zwjPict is whether curr is
+// Extended_Pictographic right after Extended_Pictographic Extend* ZWJ, linked is whether curr is an Indic consonant
+// right after a conjunct ending with a Linker, and ri is the count of the preceding Regional_Indicators.
+func graphemeBreak(prev, curr gcbProp, zwjPict, linked bool, ri int) bool {
+	switch {
+	case prev == gcbCR && curr == gcbLF: // GB3
+		return false
+	case prev == gcbControl || prev == gcbCR || prev == gcbLF: // GB4
+		return true
+	case curr == gcbControl || curr == gcbCR || curr == gcbLF: // GB5
+		return true
+	case prev == gcbL && (curr == gcbL || curr == gcbV || curr == gcbLV || curr == gcbLVT): // GB6
+		return false
+	case (prev == gcbLV || prev == gcbV) && (curr == gcbV || curr == gcbT): // GB7
+		return false
+	case (prev == gcbLVT || prev == gcbT) && curr == gcbT: // GB8
+		return false
+	case curr == gcbExtend || curr == gcbZWJ || curr == gcbSpacingMark: // GB9, GB9a
+		return false
+	case prev == gcbPrepend: // GB9b
+		return false
+	case linked: // GB9c
+		return false
+	case prev == gcbZWJ && zwjPict: // GB11
+		return false
+	case prev == gcbRI && curr == gcbRI: // GB12, GB13
+		return ri%2 == 0
+	}
+	return true // GB999
+}
+
+// endregion
+
+// region Word
+
+// wordIgnored reports whether p is ignored by WB4.
+func wordIgnored(p wbProp) bool {
+	return p == wbExtend || p == wbFormat || p == wbZWJ
+}
+
+func isAHLetter(p wbProp) bool {
+	return p == wbALetter || p == wbHebrewLetter
+}
+
+func isMidLetterQ(p wbProp) bool {
+	return p == wbMidLetter || p == wbMidNumLet || p == wbSingleQuote
+}
+
+func isMidNumQ(p wbProp) bool {
+	return p == wbMidNum || p == wbMidNumLet || p == wbSingleQuote
+}
+
+// nextWordProp returns the property of the first rune of s not ignored by WB4, and wbOther if there is none.
+func nextWordProp(s string) wbProp {
+	for _, r := range s {
+		if p := wordProp(r); !wordIgnored(p) {
+			return p
+		}
+	}
+	return wbOther
+}
+
+func firstWord(s string) int {
+	r, pos := utf8.DecodeRuneInString(s)
+	// raw is the property of the rune right before, and prev and prev2 are the ones of the runes not ignored.
+	raw := wordProp(r)
+	prev, prev2 := raw, wbOther
+	ri := 0
+	if prev == wbRI {
+		ri = 1
+	}
+	for pos < len(s) {
+		r, size := utf8.DecodeRuneInString(s[pos:])
+		curr := wordProp(r)
+		switch {
+		case raw == wbCR && curr == wbLF: // WB3
+		case raw == wbNewline || raw == wbCR || raw == wbLF: // WB3a
+			return pos
+		case curr == wbNewline || curr == wbCR || curr == wbLF: // WB3b
+			return pos
+		case raw == wbZWJ && extPictographic.has(r): // WB3c
+		case raw == wbWSegSpace && curr == wbWSegSpace: // WB3d
+		case wordIgnored(curr): // WB4
+			raw = curr
+			pos += size
+			continue
+		default:
+			if wordBreak(prev2, prev, curr, func() wbProp { return nextWordProp(s[pos+size:]) }, ri) {
+				return pos
+			}
+		}
+		if curr == wbRI {
+			ri++
+		}
+		raw, prev, prev2 = curr, curr, prev
+		pos += size
+	}
+	return pos
+}
+
+// wordBreak reports whether there is a boundary between prev and curr by WB5 to WB999, where prev2 is before prev,
+// next returns the property after curr, and ri is the count of the preceding Regional_Indicators.
+func wordBreak(prev2, prev, curr wbProp, next func() wbProp, ri int) bool {
+	switch {
+	case isAHLetter(prev) && isAHLetter(curr): // WB5
+		return false
+	case isAHLetter(prev) && isMidLetterQ(curr) && isAHLetter(next()): // WB6
+		return false
+	case isAHLetter(prev2) && isMidLetterQ(prev) && isAHLetter(curr): // WB7
+		return false
+	case prev == wbHebrewLetter && curr == wbSingleQuote: // WB7a
+		return false
+	case prev == wbHebrewLetter && curr == wbDoubleQuote && next() == wbHebrewLetter: // WB7b
+		return false
+	case prev2 == wbHebrewLetter && prev == wbDoubleQuote && curr == wbHebrewLetter: // WB7c
+		return false
+	case (prev == wbNumeric || isAHLetter(prev)) && (curr == wbNumeric || isAHLetter(curr)): // WB8, WB9, WB10
+		return false
+	case prev2 == wbNumeric && isMidNumQ(prev) && curr == wbNumeric: // WB11
+		return false
+	case prev == wbNumeric && isMidNumQ(curr) && next() == wbNumeric: // WB12
+		return false
+	case prev == wbKatakana && curr == wbKatakana: // WB13
+		return false
+	case curr == wbExtendNumLet &&
+		(isAHLetter(prev) || prev == wbNumeric || prev == wbKatakana || prev == wbExtendNumLet): // WB13a
+		return false
+	case prev == wbExtendNumLet && (isAHLetter(curr) || curr == wbNumeric || curr == wbKatakana): // WB13b
+		return false
+	case prev == wbRI && curr == wbRI: // WB15, WB16
+		return ri%2 == 0
+	}
+	return true // WB999
+}
+
+// endregion
+
+// region Line
+
+// lineState holds the context of the runes before the current one.
+type lineState struct {
+	raw      lbClass // class of the rune right before, including those attached by LB9.
+	prev     lbClass // class of the rune before, resolved by LB9 and LB10.
+	prev2    lbClass // class before prev.
+	nonSP    lbClass // class of the last rune other than SP.
+	prevRune rune    // rune of prev.
+	zwSP     bool    // whether the runes before are ZW SP*.
+	ri       int     // count of the preceding Regional_Indicators.
+}
+
+func firstLine(s string) int {
+	r, pos := utf8.DecodeRuneInString(s)
+	c := lineClass(r)
+	st := lineState{raw: c, prev2: lbAL}
+	st.push(c, r)
+	for pos < len(s) {
+		r, size := utf8.DecodeRuneInString(s[pos:])
+		c := lineClass(r)
+		if st.breaks(c, r) {
+			return pos
+		}
+		st.raw = c
+		if !((c == lbCM || c == lbZWJ) && !isLineBoundary(st.prev)) { // attached to the rune before by LB9 otherwise.
+			st.push(c, r)
+		}
+		pos += size
+	}
+	return pos
+}
+
+func isLineBoundary(c lbClass) bool {
+	return c == lbBK || c == lbCR || c == lbLF || c == lbNL || c == lbSP || c == lbZW
+}
+
+// push moves to the rune r of class c not attached to the rune before.
+func (st *lineState) push(c lbClass, r rune) {
+	if c == lbCM || c == lbZWJ { // LB10
+		c = lbAL
+	}
+	st.zwSP = c == lbZW || c == lbSP && st.zwSP
+	if c == lbRI {
+		st.ri++
+	} else {
+		st.ri = 0
+	}
+	st.prev2, st.prev, st.prevRune = st.prev, c, r
+	if c != lbSP {
+		st.nonSP = c
+	}
+}
+
+// breaks reports whether there is a break opportunity before the rune r of class c.
+func (st *lineState) breaks(c lbClass, r rune) bool {
+	prev := st.prev
+	switch {
+	case prev == lbBK: // LB4
+		return true
+	case prev == lbCR && c == lbLF: // LB5
+		return false
+	case prev == lbCR || prev == lbLF || prev == lbNL:
+		return true
+	case c == lbBK || c == lbCR || c == lbLF || c == lbNL: // LB6
+		return false
+	case c == lbSP || c == lbZW: // LB7
+		return false
+	case st.zwSP: // LB8
+		return true
+	case st.raw == lbZWJ: // LB8a
+		return false
+	case (c == lbCM || c == lbZWJ) && !isLineBoundary(prev): // LB9
+		return false
+	}
+	if c == lbCM || c == lbZWJ { // LB10
+		c = lbAL
+	}
+	spaced := prev == lbSP
+	switch {
+	case c == lbWJ || prev == lbWJ: // LB11
+		return false
+	case prev == lbGL: // LB12
+		return false
+	case c == lbGL && prev != lbSP && prev != lbBA && prev != lbHY: // LB12a
+		return false
+	case c == lbCL || c == lbCP || c == lbEX || c == lbIS || c == lbSY: // LB13
+		return false
+	case st.nonSP == lbOP: // LB14
+		return false
+	case st.nonSP == lbQU && c == lbOP: // LB15
+		return false
+	case (st.nonSP == lbCL || st.nonSP == lbCP) && c == lbNS: // LB16
+		return false
+	case st.nonSP == lbB2 && c == lbB2: // LB17
+		return false
+	case spaced: // LB18
+		return true
+	case c == lbQU || prev == lbQU: // LB19
+		return false
+	case c == lbCB || prev == lbCB: // LB20
+		return true
+	case c == lbBA || c == lbHY || c == lbNS || prev == lbBB: // LB21
+		return false
+	case st.prev2 == lbHL && (prev == lbHY || prev == lbBA): // LB21a
+		return false
+	case prev == lbSY && c == lbHL: // LB21b
+		return false
+	case c == lbIN: // LB22
+		return false
+	}
+	return !linePair(prev, c, st.prevRune, r, st.ri)
+}
+
+// linePair reports whether the pair of prev and c is kept together by LB23 to LB30b.
+func linePair(prev, c lbClass, prevRune, r rune, ri int) bool {
+	alpha := func(c lbClass) bool { return c == lbAL || c == lbHL }
+	ideo := func(c lbClass) bool { return c == lbID || c == lbEB || c == lbEM }
+	jamo := func(c lbClass) bool { return c == lbJL || c == lbJV || c == lbJT || c == lbH2 || c == lbH3 }
+	switch {
+	case alpha(prev) && c == lbNU || prev == lbNU && alpha(c): // LB23
+		return true
+	case prev == lbPR && ideo(c) || ideo(prev) && c == lbPO: // LB23a
+		return true
+	case (prev == lbPR || prev == lbPO) && alpha(c) || alpha(prev) && (c == lbPR || c == lbPO): // LB24
+		return true
+	case (prev == lbCL || prev == lbCP || prev == lbNU) && (c == lbPO || c == lbPR), // LB25
+		(prev == lbPO || prev == lbPR) && (c == lbOP || c == lbNU),
+		(prev == lbHY || prev == lbIS || prev == lbNU || prev == lbSY) && c == lbNU:
+		return true
+	case prev == lbJL && (c == lbJL || c == lbJV || c == lbH2 || c == lbH3), // LB26
+		(prev == lbJV || prev == lbH2) && (c == lbJV || c == lbJT),
+		(prev == lbJT || prev == lbH3) && c == lbJT:
+		return true
+	case jamo(prev) && c == lbPO || prev == lbPR && jamo(c): // LB27
+		return true
+	case alpha(prev) && alpha(c): // LB28
+		return true
+	case prev == lbIS && alpha(c): // LB29
+		return true
+	case (alpha(prev) || prev == lbNU) && c == lbOP && !isEastAsianWide(r), // LB30
+		prev == lbCP && !isEastAsianWide(prevRune) && (alpha(c) || c == lbNU):
+		return true
+	case prev == lbRI && c == lbRI: // LB30a
+		return ri%2 == 1
+	case prev == lbEB && c == lbEM: // LB30b
+		return true
+	}
+	return false
+}
+
+// endregion
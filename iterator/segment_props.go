@@ -0,0 +1,475 @@
+package iterator
+
+import (
+	"sort"
+	"unicode"
+)
+
+// Unicode properties for segmentation, which are not in the unicode package. They are derived from the unicode
+// package where possible, and listed from the Unicode Character Database otherwise.
+
+// runeRanges are sorted and non-overlapping inclusive ranges of runes.
+type runeRanges [][2]rune
+
+func (rs runeRanges) has(r rune) bool {
+	i := sort.Search(len(rs), func(i int) bool { return rs[i][1] >= r })
+	return i < len(rs) && rs[i][0] <= r
+}
+
+// region common
+
+var extPictographic = runeRanges{
+	{0x00A9, 0x00A9}, {0x00AE, 0x00AE}, {0x203C, 0x203C}, {0x2049, 0x2049}, {0x2122, 0x2122}, {0x2139, 0x2139},
+	{0x2194, 0x2199}, {0x21A9, 0x21AA}, {0x231A, 0x231B}, {0x2328, 0x2328}, {0x2388, 0x2388}, {0x23CF, 0x23CF},
+	{0x23E9, 0x23F3}, {0x23F8, 0x23FA}, {0x24C2, 0x24C2}, {0x25AA, 0x25AB}, {0x25B6, 0x25B6}, {0x25C0, 0x25C0},
+	{0x25FB, 0x25FE}, {0x2600, 0x2605}, {0x2607, 0x2612}, {0x2614, 0x2685}, {0x2690, 0x2705}, {0x2708, 0x2712},
+	{0x2714, 0x2714}, {0x2716, 0x2716}, {0x271D, 0x271D}, {0x2721, 0x2721}, {0x2728, 0x2728}, {0x2733, 0x2734},
+	{0x2744, 0x2744}, {0x2747, 0x2747}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757},
+	{0x2763, 0x2767}, {0x2795, 0x2797}, {0x27A1, 0x27A1}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2934, 0x2935},
+	{0x2B05, 0x2B07}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x3030, 0x3030}, {0x303D, 0x303D},
+	{0x3297, 0x3297}, {0x3299, 0x3299}, {0x1F000, 0x1F0FF}, {0x1F10D, 0x1F10F}, {0x1F12F, 0x1F12F},
+	{0x1F16C, 0x1F171}, {0x1F17E, 0x1F17F}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F1AD, 0x1F1E5},
+	{0x1F201, 0x1F20F}, {0x1F21A, 0x1F21A}, {0x1F22F, 0x1F22F}, {0x1F232, 0x1F23A}, {0x1F23C, 0x1F23F},
+	{0x1F249, 0x1F3FA}, {0x1F400, 0x1F53D}, {0x1F546, 0x1F64F}, {0x1F680, 0x1F6FF}, {0x1F774, 0x1F77F},
+	{0x1F7D5, 0x1F7FF}, {0x1F80C, 0x1F80F}, {0x1F848, 0x1F84F}, {0x1F85A, 0x1F85F}, {0x1F888, 0x1F88F},
+	{0x1F8AE, 0x1F8FF}, {0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945}, {0x1F947, 0x1FAFF}, {0x1FC00, 0x1FFFD},
+}
+
+var emojiModifierBase = runeRanges{
+	{0x261D, 0x261D}, {0x26F9, 0x26F9}, {0x270A, 0x270D}, {0x1F385, 0x1F385}, {0x1F3C2, 0x1F3C4},
+	{0x1F3C7, 0x1F3C7}, {0x1F3CA, 0x1F3CC}, {0x1F442, 0x1F443}, {0x1F446, 0x1F450}, {0x1F466, 0x1F478},
+	{0x1F47C, 0x1F47C}, {0x1F481, 0x1F483}, {0x1F485, 0x1F487}, {0x1F48F, 0x1F48F}, {0x1F491, 0x1F491},
+	{0x1F4AA, 0x1F4AA}, {0x1F574, 0x1F575}, {0x1F57A, 0x1F57A}, {0x1F590, 0x1F590}, {0x1F595, 0x1F596},
+	{0x1F645, 0x1F647}, {0x1F64B, 0x1F64F}, {0x1F6A3, 0x1F6A3}, {0x1F6B4, 0x1F6B6}, {0x1F6C0, 0x1F6C0},
+	{0x1F6CC, 0x1F6CC}, {0x1F90C, 0x1F90C}, {0x1F90F, 0x1F90F}, {0x1F918, 0x1F91F}, {0x1F926, 0x1F926},
+	{0x1F930, 0x1F939}, {0x1F93C, 0x1F93E}, {0x1F977, 0x1F977}, {0x1F9B5, 0x1F9B6}, {0x1F9B8, 0x1F9B9},
+	{0x1F9BB, 0x1F9BB}, {0x1F9CD, 0x1F9CF}, {0x1F9D1, 0x1F9DD}, {0x1FAC3, 0x1FAC5}, {0x1FAF0, 0x1FAF8},
+}
+
+func isEmojiModifier(r rune) bool {
+	return 0x1F3FB <= r && r <= 0x1F3FF
+}
+
+func isRegionalIndicator(r rune) bool {
+	return 0x1F1E6 <= r && r <= 0x1F1FF
+}
+
+// isGraphemeExtend reports the Grapheme_Extend property, which also covers emoji modifiers since Unicode 11.
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend) || isEmojiModifier(r)
+}
+
+// hangulSyllable returns the Hangul_Syllable_Type of r.
+func hangulSyllable(r rune) (l, v, t, lv, lvt bool) {
+	switch {
+	case 0x1100 <= r && r <= 0x115F, 0xA960 <= r && r <= 0xA97C:
+		l = true
+	case 0x1160 <= r && r <= 0x11A7, 0xD7B0 <= r && r <= 0xD7C6:
+		v = true
+	case 0x11A8 <= r && r <= 0x11FF, 0xD7CB <= r && r <= 0xD7FB:
+		t = true
+	case 0xAC00 <= r && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			lv = true
+		} else {
+			lvt = true
+		}
+	}
+	return
+}
+
+// endregion
+
+// region Grapheme_Cluster_Break
+
+type gcbProp uint8
+
+const (
+	gcbOther gcbProp = iota
+	gcbCR
+	gcbLF
+	gcbControl
+	gcbExtend
+	gcbZWJ
+	gcbRI
+	gcbPrepend
+	gcbSpacingMark
+	gcbL
+	gcbV
+	gcbT
+	gcbLV
+	gcbLVT
+)
+
+var gcbPrependExtra = runeRanges{
+	{0x0D4E, 0x0D4E}, {0x111C2, 0x111C3}, {0x1193F, 0x1193F}, {0x11941, 0x11941}, {0x11A3A, 0x11A3A},
+	{0x11A84, 0x11A89}, {0x11D46, 0x11D46},
+}
+
+// gcbNotSpacingMark lists spacing combining marks, which are not SpacingMark.
+var gcbNotSpacingMark = runeRanges{
+	{0x102B, 0x102C}, {0x1038, 0x1038}, {0x1062, 0x1064}, {0x1067, 0x106D}, {0x1083, 0x1083}, {0x1087, 0x108C},
+	{0x108F, 0x108F}, {0x109A, 0x109C}, {0x1A61, 0x1A61}, {0x1A63, 0x1A64}, {0xAA7B, 0xAA7B}, {0xAA7D, 0xAA7D},
+	{0x11720, 0x11721},
+}
+
+// incbConsonant lists the Indic_Conjunct_Break=Consonant property of Unicode 15.1.
+var incbConsonant = runeRanges{
+	{0x0915, 0x0939}, {0x0958, 0x095F}, {0x0978, 0x097F}, {0x0995, 0x09A8}, {0x09AA, 0x09B0}, {0x09B2, 0x09B2},
+	{0x09B6, 0x09B9}, {0x09DC, 0x09DD}, {0x09DF, 0x09DF}, {0x09F0, 0x09F1}, {0x0A95, 0x0AA8}, {0x0AAA, 0x0AB0},
+	{0x0AB2, 0x0AB3}, {0x0AB5, 0x0AB9}, {0x0AF9, 0x0AF9}, {0x0B15, 0x0B28}, {0x0B2A, 0x0B30}, {0x0B32, 0x0B33},
+	{0x0B35, 0x0B39}, {0x0B5C, 0x0B5D}, {0x0B5F, 0x0B5F}, {0x0B71, 0x0B71}, {0x0C15, 0x0C28}, {0x0C2A, 0x0C39},
+	{0x0C58, 0x0C5A}, {0x0D15, 0x0D3A},
+}
+
+// isIncbLinker reports the Indic_Conjunct_Break=Linker property of Unicode 15.1, which are viramas.
+func isIncbLinker(r rune) bool {
+	switch r {
+	case 0x094D, 0x09CD, 0x0ACD, 0x0B4D, 0x0C4D, 0x0D4D:
+		return true
+	}
+	return false
+}
+
+func graphemeProp(r rune) gcbProp {
+	switch r {
+	case '\r':
+		return gcbCR
+	case '\n':
+		return gcbLF
+	case 0x200D:
+		return gcbZWJ
+	case 0x0E33, 0x0EB3:
+		return gcbSpacingMark
+	}
+	switch l, v, t, lv, lvt := hangulSyllable(r); {
+	case l:
+		return gcbL
+	case v:
+		return gcbV
+	case t:
+		return gcbT
+	case lv:
+		return gcbLV
+	case lvt:
+		return gcbLVT
+	}
+	switch {
+	case isGraphemeExtend(r):
+		return gcbExtend
+	case isRegionalIndicator(r):
+		return gcbRI
+	case unicode.Is(unicode.Prepended_Concatenation_Mark, r) || gcbPrependExtra.has(r):
+		return gcbPrepend
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp, unicode.Cs):
+		return gcbControl
+	case unicode.Is(unicode.Mc, r) && !gcbNotSpacingMark.has(r):
+		return gcbSpacingMark
+	}
+	return gcbOther
+}
+
+// endregion
+
+// region Word_Break
+
+type wbProp uint8
+
+const (
+	wbOther wbProp = iota
+	wbCR
+	wbLF
+	wbNewline
+	wbExtend
+	wbZWJ
+	wbRI
+	wbFormat
+	wbKatakana
+	wbHebrewLetter
+	wbALetter
+	wbSingleQuote
+	wbDoubleQuote
+	wbMidNumLet
+	wbMidLetter
+	wbMidNum
+	wbNumeric
+	wbExtendNumLet
+	wbWSegSpace
+)
+
+var wbKatakanaExtra = runeRanges{
+	{0x3031, 0x3035}, {0x309B, 0x309C}, {0x30A0, 0x30A0}, {0x30FC, 0x30FC}, {0xFF70, 0xFF70},
+}
+
+var wbALetterExtra = runeRanges{
+	{0x02C2, 0x02C5}, {0x02D2, 0x02D7}, {0x02DE, 0x02DF}, {0x02E5, 0x02EB}, {0x02ED, 0x02ED}, {0x02EF, 0x02FF},
+	{0x055A, 0x055C}, {0x055E, 0x055E}, {0x058A, 0x058A}, {0x05F3, 0x05F3}, {0xA708, 0xA716}, {0xA720, 0xA721},
+	{0xA789, 0xA78A}, {0xAB5B, 0xAB5B},
+}
+
+var wbMidNumLetRunes = runeRanges{
+	{0x002E, 0x002E}, {0x2018, 0x2019}, {0x2024, 0x2024}, {0xFE52, 0xFE52}, {0xFF07, 0xFF07}, {0xFF0E, 0xFF0E},
+}
+
+var wbMidLetterRunes = runeRanges{
+	{0x003A, 0x003A}, {0x00B7, 0x00B7}, {0x0387, 0x0387}, {0x055F, 0x055F}, {0x05F4, 0x05F4}, {0x2027, 0x2027},
+	{0xFE13, 0xFE13}, {0xFE55, 0xFE55}, {0xFF1A, 0xFF1A},
+}
+
+var wbMidNumRunes = runeRanges{
+	{0x002C, 0x002C}, {0x003B, 0x003B}, {0x037E, 0x037E}, {0x0589, 0x0589}, {0x060C, 0x060D}, {0x066C, 0x066C},
+	{0x07F8, 0x07F8}, {0x2044, 0x2044}, {0xFE10, 0xFE10}, {0xFE14, 0xFE14}, {0xFE50, 0xFE50}, {0xFE54, 0xFE54},
+	{0xFF0C, 0xFF0C}, {0xFF1B, 0xFF1B},
+}
+
+// complexContext lists scripts, whose words are not separated by spaces and are segmented by dictionaries, which
+// are out of scope here.
+var complexContext = []*unicode.RangeTable{
+	unicode.Thai, unicode.Lao, unicode.Myanmar, unicode.Khmer, unicode.Tai_Le, unicode.New_Tai_Lue,
+	unicode.Tai_Tham, unicode.Tai_Viet, unicode.Ahom,
+}
+
+func wordProp(r rune) wbProp {
+	switch r {
+	case '\r':
+		return wbCR
+	case '\n':
+		return wbLF
+	case 0x000B, 0x000C, 0x0085, 0x2028, 0x2029:
+		return wbNewline
+	case 0x200D:
+		return wbZWJ
+	case '\'':
+		return wbSingleQuote
+	case '"':
+		return wbDoubleQuote
+	case 0x066B:
+		return wbNumeric
+	case 0x202F:
+		return wbExtendNumLet
+	}
+	switch {
+	case isGraphemeExtend(r) || unicode.Is(unicode.Mc, r):
+		return wbExtend
+	case isRegionalIndicator(r):
+		return wbRI
+	case unicode.Is(unicode.Prepended_Concatenation_Mark, r) || unicode.Is(unicode.Nd, r):
+		return wbNumeric
+	case unicode.Is(unicode.Cf, r) && r != 0x200B:
+		return wbFormat
+	case unicode.Is(unicode.Katakana, r) || wbKatakanaExtra.has(r):
+		return wbKatakana
+	case unicode.Is(unicode.Hebrew, r) && unicode.Is(unicode.Lo, r):
+		return wbHebrewLetter
+	case wbMidNumLetRunes.has(r):
+		return wbMidNumLet
+	case wbMidLetterRunes.has(r):
+		return wbMidLetter
+	case wbMidNumRunes.has(r):
+		return wbMidNum
+	case unicode.Is(unicode.Pc, r):
+		return wbExtendNumLet
+	case unicode.Is(unicode.Zs, r) && r != 0x00A0 && r != 0x2007:
+		return wbWSegSpace
+	case wbALetterExtra.has(r):
+		return wbALetter
+	case unicode.In(r, unicode.L, unicode.Nl, unicode.Other_Alphabetic) &&
+		!unicode.In(r, unicode.Ideographic, unicode.Hiragana) && !unicode.In(r, complexContext...):
+		return wbALetter
+	}
+	return wbOther
+}
+
+// endregion
+
+// region Line_Break
+
+type lbClass uint8
+
+const (
+	lbAL lbClass = iota
+	lbBK
+	lbCR
+	lbLF
+	lbNL
+	lbSP
+	lbZW
+	lbZWJ
+	lbCM
+	lbWJ
+	lbGL
+	lbBA
+	lbBB
+	lbB2
+	lbHY
+	lbCB
+	lbCL
+	lbCP
+	lbOP
+	lbQU
+	lbEX
+	lbIN
+	lbNS
+	lbIS
+	lbNU
+	lbPO
+	lbPR
+	lbSY
+	lbHL
+	lbID
+	lbJL
+	lbJV
+	lbJT
+	lbH2
+	lbH3
+	lbRI
+	lbEB
+	lbEM
+)
+
+// lbRunes lists runes of each class, which are not derived from general categories and scripts.
+var lbRunes = []struct {
+	class lbClass
+	runes runeRanges
+}{
+	{lbBK, runeRanges{{0x000B, 0x000C}, {0x2028, 0x2029}}},
+	{lbWJ, runeRanges{{0x2060, 0x2060}, {0xFEFF, 0xFEFF}}},
+	{lbGL, runeRanges{
+		{0x00A0, 0x00A0}, {0x034F, 0x034F}, {0x035C, 0x0362}, {0x0F08, 0x0F08}, {0x0F0C, 0x0F0C}, {0x0F12, 0x0F12},
+		{0x180E, 0x180E}, {0x2007, 0x2007}, {0x2011, 0x2011}, {0x202F, 0x202F},
+	}},
+	{lbBA, runeRanges{
+		{0x0009, 0x0009}, {0x007C, 0x007C}, {0x00AD, 0x00AD}, {0x058A, 0x058A}, {0x05BE, 0x05BE}, {0x0964, 0x0965},
+		{0x0F0B, 0x0F0B}, {0x1361, 0x1361}, {0x1680, 0x1680}, {0x17D4, 0x17D5}, {0x17D8, 0x17D8}, {0x17DA, 0x17DA},
+		{0x2000, 0x2006}, {0x2008, 0x200A}, {0x2010, 0x2010}, {0x2012, 0x2013}, {0x2027, 0x2027}, {0x205F, 0x205F},
+		{0x2E0E, 0x2E15}, {0x2E17, 0x2E17}, {0x3000, 0x3000},
+	}},
+	{lbB2, runeRanges{{0x2014, 0x2014}, {0x2E3A, 0x2E3B}}},
+	{lbBB, runeRanges{
+		{0x00B4, 0x00B4}, {0x02C8, 0x02C8}, {0x02CC, 0x02CC}, {0x02DF, 0x02DF}, {0x0F01, 0x0F04}, {0x0F06, 0x0F07},
+		{0x0F09, 0x0F0A}, {0x0FD0, 0x0FD1}, {0x0FD3, 0x0FD3}, {0x1806, 0x1806}, {0x1FFD, 0x1FFD}, {0xA874, 0xA875},
+	}},
+	{lbHY, runeRanges{{0x002D, 0x002D}}},
+	{lbCB, runeRanges{{0xFFFC, 0xFFFC}}},
+	{lbCP, runeRanges{{0x0029, 0x0029}, {0x005D, 0x005D}}},
+	{lbCL, runeRanges{
+		{0x3001, 0x3002}, {0xFE11, 0xFE12}, {0xFE50, 0xFE50}, {0xFE52, 0xFE52}, {0xFF0C, 0xFF0C}, {0xFF0E, 0xFF0E},
+		{0xFF61, 0xFF61}, {0xFF64, 0xFF64},
+	}},
+	{lbOP, runeRanges{{0x00A1, 0x00A1}, {0x00BF, 0x00BF}, {0x2E18, 0x2E18}}},
+	{lbQU, runeRanges{
+		{0x0022, 0x0022}, {0x0027, 0x0027}, {0x275B, 0x2760}, {0x2E00, 0x2E0D}, {0x2E1C, 0x2E1D}, {0x2E20, 0x2E21},
+		{0x1F676, 0x1F678},
+	}},
+	{lbEX, runeRanges{
+		{0x0021, 0x0021}, {0x003F, 0x003F}, {0x05C6, 0x05C6}, {0x061B, 0x061B}, {0x061E, 0x061F}, {0x06D4, 0x06D4},
+		{0x07F9, 0x07F9}, {0x0F0D, 0x0F11}, {0x0F14, 0x0F14}, {0x1802, 0x1803}, {0x1808, 0x1809}, {0x1944, 0x1945},
+		{0x2762, 0x2763}, {0x2CF9, 0x2CF9}, {0x2CFE, 0x2CFE}, {0x2E2E, 0x2E2E}, {0xA60E, 0xA60E}, {0xA876, 0xA877},
+		{0xFE15, 0xFE16}, {0xFE56, 0xFE57}, {0xFF01, 0xFF01}, {0xFF1F, 0xFF1F},
+	}},
+	{lbIN, runeRanges{{0x2024, 0x2026}, {0xFE19, 0xFE19}}},
+	{lbNS, runeRanges{
+		// conditional Japanese starters, i.e. small kana, are NS as in strict line breaking.
+		{0x17D6, 0x17D6}, {0x203C, 0x203D}, {0x2047, 0x2049}, {0x3005, 0x3005}, {0x301C, 0x301C}, {0x303B, 0x303C},
+		{0x3041, 0x3041}, {0x3043, 0x3043}, {0x3045, 0x3045}, {0x3047, 0x3047}, {0x3049, 0x3049}, {0x3063, 0x3063},
+		{0x3083, 0x3083}, {0x3085, 0x3085}, {0x3087, 0x3087}, {0x308E, 0x308E}, {0x3095, 0x3096}, {0x309B, 0x309E},
+		{0x30A0, 0x30A1}, {0x30A3, 0x30A3}, {0x30A5, 0x30A5}, {0x30A7, 0x30A7}, {0x30A9, 0x30A9}, {0x30C3, 0x30C3},
+		{0x30E3, 0x30E3}, {0x30E5, 0x30E5}, {0x30E7, 0x30E7}, {0x30EE, 0x30EE}, {0x30F5, 0x30F6}, {0x30FB, 0x30FE},
+		{0x31F0, 0x31FF}, {0xA015, 0xA015}, {0xFE54, 0xFE55}, {0xFF1A, 0xFF1B}, {0xFF65, 0xFF65}, {0xFF67, 0xFF70},
+		{0xFF9E, 0xFF9F},
+	}},
+	{lbIS, runeRanges{
+		{0x002C, 0x002C}, {0x002E, 0x002E}, {0x003A, 0x003B}, {0x037E, 0x037E}, {0x0589, 0x0589}, {0x060C, 0x060D},
+		{0x07F8, 0x07F8}, {0x2044, 0x2044}, {0xFE10, 0xFE10}, {0xFE13, 0xFE14},
+	}},
+	{lbNU, runeRanges{{0x066B, 0x066C}}},
+	{lbPO, runeRanges{
+		{0x0025, 0x0025}, {0x00A2, 0x00A2}, {0x00B0, 0x00B0}, {0x060B, 0x060B}, {0x066A, 0x066A}, {0x2030, 0x2037},
+		{0x20A7, 0x20A7}, {0x2103, 0x2103}, {0x2109, 0x2109}, {0x2116, 0x2116}, {0xFDFC, 0xFDFC}, {0xFE6A, 0xFE6A},
+		{0xFF05, 0xFF05}, {0xFFE0, 0xFFE0},
+	}},
+	{lbPR, runeRanges{
+		{0x0024, 0x0024}, {0x002B, 0x002B}, {0x005C, 0x005C}, {0x00A3, 0x00A5}, {0x00B1, 0x00B1}, {0x058F, 0x058F},
+		{0x09FE, 0x09FE}, {0x0E3F, 0x0E3F}, {0x17DB, 0x17DB}, {0x20A0, 0x20A6}, {0x20A8, 0x20C0}, {0x2212, 0x2213},
+		{0xFE69, 0xFE69}, {0xFF04, 0xFF04}, {0xFFE1, 0xFFE1}, {0xFFE5, 0xFFE6},
+	}},
+	{lbSY, runeRanges{{0x002F, 0x002F}}},
+}
+
+// lbIdeographic lists ranges of ideographs and other East Asian symbols, which are ID unless classified otherwise.
+var lbIdeographic = runeRanges{
+	{0x2E80, 0x2FFF}, {0x3003, 0x3004}, {0x3006, 0x3007}, {0x3012, 0x3013}, {0x3020, 0x3029}, {0x3030, 0x303A},
+	{0x303D, 0x303F}, {0x3040, 0x30FF}, {0x3100, 0x312F}, {0x3130, 0x318F}, {0x3190, 0x31EF}, {0x3200, 0x33FF},
+	{0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF}, {0xF900, 0xFAFF}, {0xFE30, 0xFE4F}, {0xFF01, 0xFF60},
+	{0xFFE0, 0xFFE6}, {0x1B000, 0x1B2FF}, {0x20000, 0x3FFFD},
+}
+
+// isEastAsianWide approximates East_Asian_Width in {F, W, H} for punctuation, which LB30 cares about.
+func isEastAsianWide(r rune) bool {
+	return 0x2E80 <= r && r <= 0xA4CF || 0xFE30 <= r && r <= 0xFE4F || 0xFF00 <= r && r <= 0xFFEF
+}
+
+func lineClass(r rune) lbClass {
+	switch r {
+	case '\r':
+		return lbCR
+	case '\n':
+		return lbLF
+	case 0x0085:
+		return lbNL
+	case ' ':
+		return lbSP
+	case 0x200B:
+		return lbZW
+	case 0x200D:
+		return lbZWJ
+	}
+	for _, c := range lbRunes {
+		if c.runes.has(r) {
+			return c.class
+		}
+	}
+	switch l, v, t, lv, lvt := hangulSyllable(r); {
+	case l:
+		return lbJL
+	case v:
+		return lbJV
+	case t:
+		return lbJT
+	case lv:
+		return lbH2
+	case lvt:
+		return lbH3
+	}
+	switch {
+	case isRegionalIndicator(r):
+		return lbRI
+	case isEmojiModifier(r):
+		return lbEM
+	case emojiModifierBase.has(r):
+		return lbEB
+	case unicode.In(r, complexContext...):
+		// no dictionary here, so complex context runes never break within words.
+		if unicode.In(r, unicode.Mn, unicode.Mc) {
+			return lbCM
+		}
+		return lbAL
+	case unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me, unicode.Cc, unicode.Cf):
+		return lbCM
+	case unicode.Is(unicode.Nd, r) && !(0xFF10 <= r && r <= 0xFF19):
+		return lbNU
+	case unicode.Is(unicode.Hebrew, r) && unicode.Is(unicode.Lo, r):
+		return lbHL
+	case unicode.Is(unicode.Ps, r):
+		return lbOP
+	case unicode.Is(unicode.Pe, r):
+		return lbCL
+	case unicode.In(r, unicode.Pi, unicode.Pf):
+		return lbQU
+	case lbIdeographic.has(r) || unicode.Is(unicode.Ideographic, r) || extPictographic.has(r) && r >= 0x1F000:
+		return lbID
+	}
+	return lbAL
+}
+
+// endregion
@@ -0,0 +1,112 @@
+package iterator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func segments(iterable Iterable[string]) (ret []string) {
+	iter := iterable.Iterator()
+	defer iter.Close()
+	for iter.MoveNext() {
+		ret = append(ret, iter.Current())
+	}
+	return
+}
+
+func TestGraphemeIterable(t *testing.T) {
+	cases := []struct {
+		str      string
+		expected []string
+	}{
+		{"", nil},
+		{"abc", []string{"a", "b", "c"}},
+		{"éa", []string{"é", "a"}},
+		{"\r\n\n", []string{"\r\n", "\n"}},
+		// family, thumbs up with skin tone, rainbow flag.
+		{"👨‍👩‍👧👍🏽🏳️‍🌈", []string{"👨‍👩‍👧", "👍🏽", "🏳️‍🌈"}},
+		// flags of Japan, the US and a lone regional indicator.
+		{"🇯🇵🇺🇸🇫", []string{"🇯🇵", "🇺🇸", "🇫"}},
+		// a ZWJ not joining pictographs.
+		{"a\u200db", []string{"a\u200d", "b"}},
+		// hangul syllable of conjoining jamo, and precomposed syllables.
+		{"각한국", []string{"각", "한", "국"}},
+		// devanagari with a conjunct of a virama and a spacing mark.
+		{"नमस्ते", []string{"न", "म", "स्ते"}},
+		// a virama not followed by a consonant, and a conjunct through a nukta.
+		{"क्‍अक़्ष", []string{"क्‍", "अ", "क़्ष"}},
+	}
+	for _, c := range cases {
+		if actual := segments(GraphemeIterable(c.str)); !reflect.DeepEqual(c.expected, actual) {
+			t.Fatalf("case: %q, expected: %q, actual: %q\n", c.str, c.expected, actual)
+		}
+	}
+	iter := GraphemeIterable("a👍🏽b").Iterator().(SegmentIterator)
+	var offsets []int
+	for iter.MoveNext() {
+		offsets = append(offsets, iter.Offset())
+	}
+	if expected := []int{0, 1, 9}; !reflect.DeepEqual(expected, offsets) || iter.Offset() != 10 {
+		t.Fatalf("case: offsets, expected: %v, actual: %v, %v\n", expected, offsets, iter.Offset())
+	}
+}
+
+func TestWordIterable(t *testing.T) {
+	cases := []struct {
+		str      string
+		expected []string
+	}{
+		{"The quick (\"brown\") fox can't jump 32.3 feet, right?", []string{
+			"The", " ", "quick", " ", "(", "\"", "brown", "\"", ")", " ", "fox", " ", "can't", " ", "jump", " ",
+			"32.3", " ", "feet", ",", " ", "right", "?",
+		}},
+		{"e.g. 1,000 a_b x2  \r\nz", []string{"e.g", ".", " ", "1,000", " ", "a_b", " ", "x2", "  ", "\r\n", "z"}},
+		{"日本語カタカナ", []string{"日", "本", "語", "カタカナ"}},
+		{"cáfé 👍🏽!", []string{"cáfé", " ", "👍🏽", "!"}},
+		{"🇯🇵🇺🇸", []string{"🇯🇵", "🇺🇸"}},
+	}
+	for _, c := range cases {
+		if actual := segments(WordIterable(c.str)); !reflect.DeepEqual(c.expected, actual) {
+			t.Fatalf("case: %q, expected: %q, actual: %q\n", c.str, c.expected, actual)
+		}
+	}
+}
+
+func TestLineIterable(t *testing.T) {
+	cases := []struct {
+		str      string
+		expected []string
+	}{
+		{"The quick brown fox.", []string{"The ", "quick ", "brown ", "fox."}},
+		{"well-known (really) \"quote\"  next\nline", []string{
+			"well-", "known ", "(really) ", "\"quote\"  ", "next\n", "line",
+		}},
+		{"$9.99 10% a/b", []string{"$9.99 ", "10% ", "a/", "b"}},
+		{"日本語です。次", []string{"日", "本", "語", "で", "す。", "次"}},
+		{"a\u00a0b c\u200bd", []string{"a\u00a0b ", "c\u200b", "d"}},
+		{"👍🏽👍", []string{"👍🏽", "👍"}},
+	}
+	for _, c := range cases {
+		if actual := segments(LineIterable(c.str)); !reflect.DeepEqual(c.expected, actual) {
+			t.Fatalf("case: %q, expected: %q, actual: %q\n", c.str, c.expected, actual)
+		}
+	}
+}
+
+func FuzzSegments(f *testing.F) {
+	f.Add("👨‍👩‍👧 can't 32.3\r\n日本語")
+	f.Fuzz(func(t *testing.T, str string) {
+		for _, iterable := range []Iterable[string]{GraphemeIterable(str), WordIterable(str), LineIterable(str)} {
+			var joined string
+			for _, seg := range segments(iterable) {
+				if seg == "" {
+					t.Fatalf("empty segment of %q\n", str)
+				}
+				joined += seg
+			}
+			if joined != str {
+				t.Fatalf("expected: %q, actual: %q\n", str, joined)
+			}
+		}
+	})
+}
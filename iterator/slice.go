@@ -23,14 +23,41 @@ func SliceIterator[T ~[]E, E any](slice T) Iterator[E] {
 	}
 }
 
+// Reverse returns an Iterable of elements of the slice in reverse order.
+func (s SliceIterable[E]) Reverse() Iterable[E] {
+	return seekReverseIterable[E]{inner: s}
+}
+
 func (s *sliceIterator[E]) MoveNext() bool {
 	if s.idx+1 >= len(s.inner) {
+		s.idx = len(s.inner)
 		return false
 	}
 	s.idx++
 	return true
 }
 
+func (s *sliceIterator[E]) MovePrev() bool {
+	if s.idx-1 < 0 {
+		s.idx = -1
+		return false
+	}
+	s.idx--
+	return true
+}
+
+func (s *sliceIterator[E]) Seek(pos int) {
+	s.idx = clamp(pos, len(s.inner)) - 1
+}
+
+func (s *sliceIterator[E]) Pos() int {
+	return clamp(s.idx+1, len(s.inner))
+}
+
+func (s *sliceIterator[E]) Len() int {
+	return len(s.inner)
+}
+
 func (s *sliceIterator[E]) Current() E {
 	return s.inner[s.idx]
 }
@@ -0,0 +1,58 @@
+package stream
+
+import "github.com/not2dim/gostream/iterator"
+
+// region Async
+
+// asyncStage is implemented by stages splitting the pipeline, whose upstream runs on its own goroutine.
+type asyncStage interface {
+	// asyncSource returns the source Iterable of the downstream segment, which runs pipelines, from the async
+	// stage itself as the terminal to the source, on a new goroutine per Iterator.
+	asyncSource(pipelines []pipeline, conf *meta, hooks []func()) iterator.Iterable[any]
+}
+
+type opAsync[E any] struct {
+	base[E]
+	n int
+}
+
+func newOpAsync[E any](meta *meta, upstream pipeline, n int) (ret *opAsync[E]) {
+	if n < 0 {
+		panic("negative buffer size of Async")
+	}
+	ret = &opAsync[E]{n: n}
+	// the downstream always pulls elements from the channel one at a time.
+	ret.base = base[E]{Meta: meta.SetSinkIterable(true), Prev: upstream, Curr: ret}
+	return
+}
+
+func (f *opAsync[E]) WrapSink(down sink) sink {
+	return down
+}
+
+func (f *opAsync[E]) asyncSource(pipelines []pipeline, conf *meta, hooks []func()) iterator.Iterable[any] {
+	return asyncIterable{pipelines: pipelines, conf: conf, n: f.n, hooks: hooks}
+}
+
+// endregion
+
+// region asyncIterable
+
+type asyncIterable struct {
+	pipelines []pipeline
+	conf      *meta
+	n         int
+	hooks     []func()
+}
+
+func (a asyncIterable) Iterator() iterator.Iterator[any] {
+	return startChanneled[any](a.pipelines, a.conf, a.n, a.hooks)
+}
+
+// Size returns the size of the source of the pipeline, the same as what the source of a pipeline without
+// Async reports to its stages.
+func (a asyncIterable) Size() (n uint64, known bool) {
+	return a.pipelines[len(a.pipelines)-1].GetSource().Size()
+}
+
+// endregion
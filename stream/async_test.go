@@ -0,0 +1,86 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStreamAsync(t *testing.T) {
+	square := func(v int) int { return v * v }
+	actual := Range(0, 100).Map(square).Async(4).Filter(func(v int) bool { return v%2 == 0 }).Collect()
+	expected := Range(0, 100).Map(square).Filter(func(v int) bool { return v%2 == 0 }).Collect()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: collect, expected: %v, actual: %v\n", expected, actual)
+	}
+
+	var closed int
+	actual = Range(0, 1000000).OnClose(func() { closed++ }).Async(0).Async(8).Limit(3).Collect()
+	if expected = []int{0, 1, 2}; !reflect.DeepEqual(expected, actual) || closed != 1 {
+		t.Fatalf("case: short-circuited, expected: %v, actual: %v, closed: %v\n", expected, actual, closed)
+	}
+
+	iter := Range(0, 10).SortBy(func(u, v int) int { return v - u }).Async(2).Iterator()
+	actual = nil
+	for iter.MoveNext() {
+		actual = append(actual, iter.Current())
+	}
+	iter.Close()
+	if expected = []int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}; !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: iterator, expected: %v, actual: %v\n", expected, actual)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != "upstream" {
+				t.Fatalf("case: panicked, actual: %v\n", r)
+			}
+		}()
+		Range(0, 100).Peek(func(v int) {
+			if v == 50 {
+				panic("upstream")
+			}
+		}).Async(1).Count()
+	}()
+}
+
+func TestStreamAsyncStages(t *testing.T) {
+	stm := Range(0, 10).Filter(func(v int) bool { return v%2 == 0 }).Async(4).Map(func(v int) int { return v })
+	if kind := Describe(stm)[2].Kind; kind != "async" {
+		t.Fatalf("expected: %v, actual: %v\n", "async", kind)
+	}
+	if mode := iteratorMode(reflectBaseMeta(stm), reflectBaseCurr(stm)); mode != modeSink {
+		t.Fatalf("expected: %v, actual: %v\n", modeSink, mode)
+	}
+
+	reg := NewMemoryMetrics()
+	WithMetrics(stm, "async", reg).Count()
+	if v := reg.Counter(MetricPipelineRuns, Labels{"pipeline": "async"}); v != 1 {
+		t.Fatalf("expected: %v, actual: %v\n", 1, v)
+	}
+	for _, tc := range []struct {
+		name     string
+		labels   Labels
+		expected float64
+	}{
+		{MetricStageElementsIn, Labels{"pipeline": "async", "stage": "1", "kind": "filter"}, 10},
+		{MetricStageElementsIn, Labels{"pipeline": "async", "stage": "2", "kind": "async"}, 5},
+		{MetricStageElementsOut, Labels{"pipeline": "async", "stage": "2", "kind": "async"}, 5},
+		{MetricStageElementsIn, Labels{"pipeline": "async", "stage": "3", "kind": "map"}, 5},
+	} {
+		if v := reg.Counter(tc.name, tc.labels); v != tc.expected {
+			t.Fatalf("metric: %v%v, expected: %v, actual: %v\n", tc.name, tc.labels, tc.expected, v)
+		}
+	}
+}
+
+func TestSinkIteratorFilter(t *testing.T) {
+	iter := Range(0, 10).Filter(func(v int) bool { return v%3 == 0 }).Iterator()
+	defer iter.Close()
+	var actual []int
+	for iter.MoveNext() {
+		actual = append(actual, iter.Current())
+	}
+	if expected := []int{0, 3, 6, 9}; !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected: %v, actual: %v\n", expected, actual)
+	}
+}
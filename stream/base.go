@@ -1,11 +1,19 @@
 package stream
 
-import "github.com/not2dim/gostream/iterator"
+import (
+	"github.com/not2dim/gostream/iterator"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
 
 type pipeline interface {
 	GetSource() iterator.Iterable[any]
 	GetUpstream() pipeline
+	GetMeta() *meta
 	WrapSink(down sink) sink
+	// MarkConsumed marks the pipeline consumed by a terminal operation, and returns whether it was consumed before.
+	MarkConsumed() (consumed bool)
 }
 
 type sink interface {
@@ -15,10 +23,16 @@ type sink interface {
 	Close()
 }
 
+// wrapperSink is implemented by sinks wrapping the sink of a stage in a run, e.g. to measure or trace it.
+type wrapperSink interface {
+	unwrap() sink
+}
+
 type base[E any] struct {
-	Meta *meta
-	Prev pipeline
-	Curr pipeline
+	Meta     *meta
+	Prev     pipeline
+	Curr     pipeline
+	consumed uint32
 }
 
 func (b *base[E]) GetSource() iterator.Iterable[any] {
@@ -29,6 +43,14 @@ func (b *base[E]) GetUpstream() pipeline {
 	return b.Prev
 }
 
+func (b *base[E]) GetMeta() *meta {
+	return b.Meta
+}
+
+func (b *base[E]) MarkConsumed() (consumed bool) {
+	return !atomic.CompareAndSwapUint32(&b.consumed, 0, 1)
+}
+
 func (b *base[E]) WrapSink(down sink) sink {
 	return baseSink{down: down}
 }
@@ -58,10 +80,10 @@ func (b baseSink) Close() {
 // endregion
 
 func (b *base[E]) Skip(n uint64) Stream[E] {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return b
 	} else if b.Meta.MaxSize() <= n {
-		return newEmptyHeader[E]()
+		return b.empty()
 	}
 	return newOpSkip[E](b.Meta.Copy(), b.Curr, n)
 }
@@ -69,42 +91,51 @@ func (b *base[E]) Skip(n uint64) Stream[E] {
 func (b *base[E]) Limit(n uint64) Stream[E] {
 	if b.Meta.MaxSize() <= n {
 		return b
-	} else if b.Meta.MaxSize() == 0 {
-		return newEmptyHeader[E]()
+	} else if n == 0 {
+		return b.empty()
 	}
 	return newOpLimit[E](b.Meta.Copy(), b.Curr, n)
 }
 
+// empty returns a new Stream[E] of no elements in place of b, which still runs b without pulling any element if b
+// holds OnClose hooks, so that they run.
+func (b *base[E]) empty() Stream[E] {
+	if b.Meta.Hooked() {
+		return newOpLimit[E](b.Meta.Copy(), b.Curr, 0)
+	}
+	return newEmptyHeader[E]()
+}
+
 func (b *base[E]) Filter(pred func(v E) bool) Stream[E] {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return b
 	}
 	return newOpFilter(b.Meta.Copy(), b.Curr, pred)
 }
 
 func (b *base[E]) Peek(act func(v E)) Stream[E] {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return b
 	}
 	return newOpPeek(b.Meta.Copy(), b.Curr, act)
 }
 
 func (b *base[E]) Cond(cond func(v E) bool) Stream[E] {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return b
 	}
 	return newOpCond(b.Meta.Copy(), b.Curr, cond)
 }
 
 func (b *base[E]) Distinct() Stream[E] {
-	if b.Meta.Distinct() || b.Meta.MaxSize() == 0 {
+	if b.Meta.Distinct() || b.Meta.Empty() {
 		return b
 	}
 	return newOpDistinct[E](b.Meta.Copy(), b.Curr)
 }
 
 func (b *base[E]) DistinctBy(id func(v E) any) Stream[E] {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return b
 	}
 	return newOpDistinctBy[E](b.Meta.Copy(), b.Curr, id)
@@ -112,7 +143,7 @@ func (b *base[E]) DistinctBy(id func(v E) any) Stream[E] {
 
 func (b *base[E]) MinBy(cmp func(u E, v E) int) Nullable[E] {
 	var min Nullable[E]
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return min
 	}
 	newOpForeach(b.Curr, func(v E) {
@@ -130,7 +161,7 @@ func (b *base[E]) MinBy(cmp func(u E, v E) int) Nullable[E] {
 
 func (b *base[E]) MaxBy(cmp func(u E, v E) int) Nullable[E] {
 	var max Nullable[E]
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return max
 	}
 	newOpForeach(b.Curr, func(v E) {
@@ -148,7 +179,7 @@ func (b *base[E]) MaxBy(cmp func(u E, v E) int) Nullable[E] {
 
 func (b *base[E]) First() Nullable[E] {
 	var first Nullable[E]
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return first
 	}
 	newOpForCond(b.Curr, func(v E) bool {
@@ -160,24 +191,103 @@ func (b *base[E]) First() Nullable[E] {
 
 func (b *base[E]) Last() Nullable[E] {
 	var last Nullable[E]
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return last
 	}
-	newOpForeach(b.Curr, func(v E) {
+	term := newOpForeach(b.Curr, func(v E) {
 		last = Nullable[E]{Val: v, OK: true}
-	}, nil, nil).Terminate()
+	}, nil, nil)
+	if h, ok := b.Curr.(*header[E]); ok {
+		// seeks right to the last element, if the source is random-access.
+		consume([]pipeline{term, h})
+		run(wrapIterable[E](lastIterable[E]{inner: h.src}), term.WrapSink(nil))
+		return last
+	}
+	term.Terminate()
 	return last
 }
 
 func (b *base[E]) SortBy(cmp func(u E, v E) int) Stream[E] {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return b
 	}
 	return newOpSortBy(b.Meta.Copy(), b.Curr, cmp)
 }
 
+func (b *base[E]) Reverse() Stream[E] {
+	if b.Meta.Empty() {
+		return b
+	}
+	return newOpReverse[E](b.Meta.Copy(), b.Curr)
+}
+
+func (b *base[E]) Sample(k uint64, rng *rand.Rand) Stream[E] {
+	if b.Meta.Empty() {
+		return b
+	} else if k == 0 {
+		return b.empty()
+	}
+	return newOpSample[E](b.Meta.Copy(), b.Curr, k, newRand(rng))
+}
+
+func (b *base[E]) SampleWeighted(k uint64, weight func(v E) float64, rng *rand.Rand) Stream[E] {
+	if b.Meta.Empty() {
+		return b
+	} else if k == 0 {
+		return b.empty()
+	}
+	return newOpSampleWeighted(b.Meta.Copy(), b.Curr, k, weight, newRand(rng))
+}
+
+func (b *base[E]) SampleFraction(p float64, rng *rand.Rand) Stream[E] {
+	if b.Meta.Empty() || p >= 1 {
+		return b
+	} else if p <= 0 {
+		return b.empty()
+	}
+	rng = newRand(rng)
+	return newOpFilter(b.Meta.Copy(), b.Curr, func(_ E) bool {
+		return rng.Float64() < p
+	})
+}
+
+func (b *base[E]) Shuffle(rng *rand.Rand) Stream[E] {
+	if b.Meta.Empty() {
+		return b
+	}
+	return newOpShuffle[E](b.Meta.Copy(), b.Curr, newRand(rng))
+}
+
+func (b *base[E]) Throttle(n uint64, per time.Duration, clock Clock) Stream[E] {
+	if b.Meta.Empty() {
+		return b
+	}
+	return newOpThrottle[E](b.Meta.Copy(), b.Curr, n, per, newClock(clock))
+}
+
+func (b *base[E]) Delay(d time.Duration, clock Clock) Stream[E] {
+	if b.Meta.Empty() {
+		return b
+	}
+	return newOpDelay[E](b.Meta.Copy(), b.Curr, d, newClock(clock))
+}
+
+func (b *base[E]) RateLimit(rate float64, burst uint64, clock Clock) Stream[E] {
+	if b.Meta.Empty() {
+		return b
+	}
+	return newOpRateLimit[E](b.Meta.Copy(), b.Curr, rate, burst, newClock(clock))
+}
+
+func (b *base[E]) Debounce(d time.Duration, clock Clock) Stream[E] {
+	if b.Meta.Empty() {
+		return b
+	}
+	return newOpDebounce[E](b.Meta.Copy(), b.Curr, d, newClock(clock))
+}
+
 func (b *base[E]) Map(mapper func(v E) E) Stream[E] {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return b
 	}
 	return newOpMap(b.Meta.Copy(), b.Curr, mapper)
@@ -185,14 +295,14 @@ func (b *base[E]) Map(mapper func(v E) E) Stream[E] {
 
 func mapToAny[S any, T any](up Stream[S], mapper func(v S) T) (down Stream[T]) {
 	meta := reflectBaseMeta(up)
-	if meta.MaxSize() == 0 {
+	if meta.Empty() {
 		return newEmptyHeader[T]()
 	}
 	return newOpMapToAny(meta.Copy(), reflectBaseCurr(up), mapper)
 }
 
 func (b *base[E]) FlatMap(mapper func(v E) Stream[E]) Stream[E] {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return b
 	}
 	return newOpFlatMap(b.Meta.Copy(), b.Curr, mapper)
@@ -200,15 +310,22 @@ func (b *base[E]) FlatMap(mapper func(v E) Stream[E]) Stream[E] {
 
 func flatMapToAny[S any, T any](up Stream[S], mapper func(v S) Stream[T]) (down Stream[T]) {
 	meta := reflectBaseMeta(up)
-	if meta.MaxSize() == 0 {
+	if meta.Empty() {
 		return newEmptyHeader[T]()
 	}
 	return newOpFlatMapToAny(meta.Copy(), reflectBaseCurr(up), mapper)
 }
 
+func (b *base[E]) Async(n int) Stream[E] {
+	if b.Meta.Empty() {
+		return b
+	}
+	return newOpAsync[E](b.Meta.Copy(), b.Curr, n)
+}
+
 func (b *base[E]) Count() uint64 {
 	var cnt uint64
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return 0
 	}
 	var sizeKnown bool
@@ -230,7 +347,7 @@ func (b *base[E]) Count() uint64 {
 }
 
 func (b *base[E]) Collect() []E {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return nil
 	}
 	var ret []E
@@ -249,7 +366,7 @@ func collectToAny[C any, E any, R any](up Stream[E],
 	accumulator func(b C, a E) C,
 	finisher func(b C) R) R {
 	meta := reflectBaseMeta(up)
-	if meta.MaxSize() == 0 {
+	if meta.Empty() {
 		return finisher(supplier(0, true))
 	}
 	var container C
@@ -265,7 +382,7 @@ func collectToAny[C any, E any, R any](up Stream[E],
 
 func (b *base[E]) Reduce(id E, accum func(b, a E) E) E {
 	var bs = id
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return bs
 	}
 	newOpForeach(b.Curr, func(v E) { bs = accum(bs, v) }, nil, nil).Terminate()
@@ -273,21 +390,25 @@ func (b *base[E]) Reduce(id E, accum func(b, a E) E) E {
 }
 
 func (b *base[E]) Foreach(act func(v E)) {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return
 	}
 	newOpForeach(b.Curr, act, nil, nil).Terminate()
 }
 
 func (b *base[E]) ForCond(cond func(v E) bool) {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return
 	}
 	newOpForCond(b.Curr, cond, nil, nil).Terminate()
 }
 
+func (b *base[E]) OnClose(hook func()) Stream[E] {
+	return newOpOnClose[E](b.Meta.Copy().SetHooked(true), b.Curr, hook)
+}
+
 func (b *base[E]) Iterator() iterator.Iterator[E] {
-	if b.Meta.MaxSize() == 0 {
+	if b.Meta.Empty() {
 		return iterator.EmptyIterator[E]{}
 	}
 	return newOpIterator[E](b.Meta, b.Curr).Build()
@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and sleeps for pacing operations like Throttle, so that tests can use a FakeClock
+// instead of sleeping.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// realClock is the Clock of package time, used when a nil Clock is given.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// newClock returns clock, or the real Clock if clock is nil.
+func newClock(clock Clock) Clock {
+	if clock == nil {
+		return realClock{}
+	}
+	return clock
+}
+
+// ContextClock returns a Clock like clock, or the real Clock if clock is nil, whose Sleep returns early once ctx
+// is done, and fails the run of Stream sleeping by ctx.Err().
+// Sleeping on the real Clock is interrupted immediately, while sleeping on other Clocks is checked before and after.
+func ContextClock(ctx context.Context, clock Clock) Clock {
+	return contextClock{ctx: ctx, inner: newClock(clock)}
+}
+
+type contextClock struct {
+	ctx   context.Context
+	inner Clock
+}
+
+func (c contextClock) Now() time.Time {
+	return c.inner.Now()
+}
+
+func (c contextClock) Sleep(d time.Duration) {
+	if err := c.ctx.Err(); err != nil {
+		fail(err)
+	}
+	if _, ok := c.inner.(realClock); !ok {
+		c.inner.Sleep(d)
+	} else if d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-c.ctx.Done():
+		}
+	}
+	if err := c.ctx.Err(); err != nil {
+		fail(err)
+	}
+}
+
+// FakeClock is a Clock for tests, whose time only moves when it sleeps or is advanced.
+type FakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	slept time.Duration
+}
+
+// NewFakeClock returns a FakeClock starting at start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep advances the time by d immediately.
+func (c *FakeClock) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.slept += d
+}
+
+// Advance advances the time by d without counting it as slept, e.g. to emulate time spent elsewhere.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Slept returns the total duration slept.
+func (c *FakeClock) Slept() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.slept
+}
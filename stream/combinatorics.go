@@ -0,0 +1,461 @@
+package stream
+
+import (
+	"github.com/not2dim/gostream/iterator"
+	"math"
+	"math/bits"
+)
+
+// Product returns a new Stream of the cartesian product of a and b.
+// Elements of a are iterated lazily, while all elements of b are buffered on the first iteration.
+func Product[A any, B any](a Stream[A], b Stream[B]) Stream[iterator.Pair[A, B]] {
+	maxSize, ok := mulSize(reflectBaseMeta(a).MaxSize(), reflectBaseMeta(b).MaxSize())
+	if !ok {
+		maxSize = math.MaxUint64
+	}
+	if maxSize == 0 {
+		return newEmptyHeader[iterator.Pair[A, B]]()
+	}
+	return newHeader[iterator.Pair[A, B]](
+		defaultMeta.Copy().SetMaxSize(maxSize),
+		productIterable[A, B]{a: a, b: b},
+	)
+}
+
+// ProductN returns a new Stream of the n-ary cartesian product of ss, and each element is a new slice
+// holding one element of every input Stream in order.
+// Elements of ss[0] are iterated lazily, while all elements of ss[1:] are buffered on the first iteration.
+func ProductN[E any](ss ...Stream[E]) Stream[[]E] {
+	if len(ss) == 0 {
+		return Of([]E{})
+	}
+	var maxSize uint64 = 1
+	for _, s := range ss {
+		size := reflectBaseMeta(s).MaxSize()
+		if size == 0 {
+			return newEmptyHeader[[]E]()
+		}
+		var ok bool
+		if maxSize, ok = mulSize(maxSize, size); !ok {
+			maxSize = math.MaxUint64
+		}
+	}
+	return newHeader[[]E](
+		defaultMeta.Copy().SetMaxSize(maxSize),
+		productNIterable[E]{ss: ss},
+	)
+}
+
+// Combinations returns a new Stream of all k-combinations of slc in lexicographic order of indexes.
+// Each element is a new slice of length k.
+func Combinations[T ~[]E, E any](slc T, k int) Stream[[]E] {
+	if k < 0 || k > len(slc) {
+		return newEmptyHeader[[]E]()
+	}
+	itera := combinationIterable[E]{elems: slc, k: k}
+	return newHeader[[]E](sizedMeta(itera.Size()), itera)
+}
+
+// Permutations returns a new Stream of all permutations of slc in lexicographic order of indexes.
+// Each element is a new slice of length len(slc).
+func Permutations[T ~[]E, E any](slc T) Stream[[]E] {
+	itera := permutationIterable[E]{elems: slc}
+	return newHeader[[]E](sizedMeta(itera.Size()), itera)
+}
+
+// PowerSet returns a new Stream of all subsets of slc, ordered by their sizes first,
+// and then by lexicographic order of indexes.
+func PowerSet[T ~[]E, E any](slc T) Stream[[]E] {
+	itera := powerSetIterable[E]{elems: slc}
+	return newHeader[[]E](sizedMeta(itera.Size()), itera)
+}
+
+// sizedMeta returns a new meta limited to the given size, if the size is known.
+func sizedMeta(size uint64, known bool) *meta {
+	if known {
+		return defaultMeta.Copy().SetMaxSize(size)
+	}
+	return defaultMeta.Copy()
+}
+
+// sourceSize returns the exact size of s, if s is a source Stream without any operation.
+func sourceSize(s any) (n uint64, known bool) {
+	if reflectBaseMeta(s).MaxSize() == 0 {
+		return 0, true
+	}
+	curr := reflectBaseCurr(s)
+	if curr == nil || curr.GetUpstream() != nil || curr.GetSource() == nil {
+		return 0, false
+	}
+	return curr.GetSource().Size()
+}
+
+// binomial returns C(n, k), and ok equals false on overflow.
+func binomial(n, k uint64) (c uint64, ok bool) {
+	if k > n {
+		return 0, true
+	}
+	if k > n-k {
+		k = n - k
+	}
+	c = 1
+	for i := uint64(1); i <= k; i++ {
+		hi, lo := bits.Mul64(c, n-k+i)
+		if hi >= i {
+			return 0, false
+		}
+		c, _ = bits.Div64(hi, lo, i)
+	}
+	return c, true
+}
+
+// factorial returns n!, and ok equals false on overflow.
+func factorial(n uint64) (f uint64, ok bool) {
+	f = 1
+	for i := uint64(2); i <= n; i++ {
+		if f, ok = mulSize(f, i); !ok {
+			return 0, false
+		}
+	}
+	return f, true
+}
+
+// region productIterable
+
+type productIterable[A any, B any] struct {
+	a Stream[A]
+	b Stream[B]
+}
+
+func (p productIterable[A, B]) Iterator() iterator.Iterator[iterator.Pair[A, B]] {
+	return &productIterator[A, B]{a: p.a, b: p.b}
+}
+
+func (p productIterable[A, B]) Size() (n uint64, known bool) {
+	na, ka := sourceSize(p.a)
+	nb, kb := sourceSize(p.b)
+	if !ka || !kb {
+		return 0, false
+	}
+	if n, known = mulSize(na, nb); !known {
+		return 0, false
+	}
+	return n, true
+}
+
+type productIterator[A any, B any] struct {
+	a     Stream[A]
+	b     Stream[B]
+	iterA iterator.Iterator[A]
+	bs    []B
+	idx   int
+	done  bool
+	curr  iterator.Pair[A, B]
+}
+
+func (p *productIterator[A, B]) MoveNext() bool {
+	if p.done {
+		return false
+	}
+	if p.iterA == nil {
+		p.bs = p.b.Collect()
+		if len(p.bs) == 0 {
+			p.done = true
+			return false
+		}
+		p.iterA = p.a.Iterator()
+		p.idx = len(p.bs)
+	}
+	if p.idx >= len(p.bs) {
+		if !p.iterA.MoveNext() {
+			p.done = true
+			return false
+		}
+		p.curr.First = p.iterA.Current()
+		p.idx = 0
+	}
+	p.curr.Second = p.bs[p.idx]
+	p.idx++
+	return true
+}
+
+func (p *productIterator[A, B]) Current() iterator.Pair[A, B] {
+	return p.curr
+}
+
+func (p *productIterator[A, B]) Close() {
+	p.done = true
+	if p.iterA != nil {
+		p.iterA.Close()
+		p.iterA = nil
+	}
+}
+
+// endregion
+
+// region productNIterable
+
+type productNIterable[E any] struct {
+	ss []Stream[E]
+}
+
+func (p productNIterable[E]) Iterator() iterator.Iterator[[]E] {
+	return &productNIterator[E]{ss: p.ss}
+}
+
+func (p productNIterable[E]) Size() (n uint64, known bool) {
+	n = 1
+	for _, s := range p.ss {
+		var size uint64
+		if size, known = sourceSize(s); !known {
+			return 0, false
+		}
+		if n, known = mulSize(n, size); !known {
+			return 0, false
+		}
+	}
+	return n, true
+}
+
+type productNIterator[E any] struct {
+	ss       []Stream[E]
+	head     iterator.Iterator[E]
+	rest     [][]E
+	idxs     []int
+	currHead E
+	done     bool
+	curr     []E
+}
+
+func (p *productNIterator[E]) MoveNext() bool {
+	if p.done {
+		return false
+	}
+	if p.head == nil {
+		p.rest = make([][]E, len(p.ss)-1)
+		for i, s := range p.ss[1:] {
+			if p.rest[i] = s.Collect(); len(p.rest[i]) == 0 {
+				p.done = true
+				return false
+			}
+		}
+		p.idxs = make([]int, len(p.rest))
+		p.head = p.ss[0].Iterator()
+		if !p.head.MoveNext() {
+			p.done = true
+			return false
+		}
+		p.currHead = p.head.Current()
+	} else {
+		i := len(p.idxs) - 1
+		for ; i >= 0; i-- {
+			if p.idxs[i]++; p.idxs[i] < len(p.rest[i]) {
+				break
+			}
+			p.idxs[i] = 0
+		}
+		if i < 0 {
+			if !p.head.MoveNext() {
+				p.done = true
+				return false
+			}
+			p.currHead = p.head.Current()
+		}
+	}
+	p.curr = make([]E, 1+len(p.rest))
+	p.curr[0] = p.currHead
+	for i, idx := range p.idxs {
+		p.curr[i+1] = p.rest[i][idx]
+	}
+	return true
+}
+
+func (p *productNIterator[E]) Current() []E {
+	return p.curr
+}
+
+func (p *productNIterator[E]) Close() {
+	p.done = true
+	if p.head != nil {
+		p.head.Close()
+		p.head = nil
+	}
+}
+
+// endregion
+
+// region combinationIterable
+
+type combinationIterable[E any] struct {
+	elems []E
+	k     int
+}
+
+func (c combinationIterable[E]) Iterator() iterator.Iterator[[]E] {
+	return &combinationIterator[E]{elems: c.elems, k: c.k}
+}
+
+func (c combinationIterable[E]) Size() (n uint64, known bool) {
+	if n, known = binomial(uint64(len(c.elems)), uint64(c.k)); !known {
+		return 0, false
+	}
+	return n, true
+}
+
+type combinationIterator[E any] struct {
+	iterator.EmptyIterator[[]E]
+	elems []E
+	k     int
+	idxs  []int // indexes of the current combination, nil before the first one.
+	done  bool
+	curr  []E
+}
+
+func (c *combinationIterator[E]) MoveNext() bool {
+	if c.done {
+		return false
+	}
+	n := len(c.elems)
+	if c.idxs == nil {
+		c.idxs = make([]int, c.k)
+		for i := range c.idxs {
+			c.idxs[i] = i
+		}
+	} else {
+		i := c.k - 1
+		for i >= 0 && c.idxs[i] == n-c.k+i {
+			i--
+		}
+		if i < 0 {
+			c.done = true
+			return false
+		}
+		c.idxs[i]++
+		for j := i + 1; j < c.k; j++ {
+			c.idxs[j] = c.idxs[j-1] + 1
+		}
+	}
+	c.curr = make([]E, c.k)
+	for i, idx := range c.idxs {
+		c.curr[i] = c.elems[idx]
+	}
+	return true
+}
+
+func (c *combinationIterator[E]) Current() []E {
+	return c.curr
+}
+
+// endregion
+
+// region permutationIterable
+
+type permutationIterable[E any] struct {
+	elems []E
+}
+
+func (p permutationIterable[E]) Iterator() iterator.Iterator[[]E] {
+	return &permutationIterator[E]{elems: p.elems}
+}
+
+func (p permutationIterable[E]) Size() (n uint64, known bool) {
+	if n, known = factorial(uint64(len(p.elems))); !known {
+		return 0, false
+	}
+	return n, true
+}
+
+type permutationIterator[E any] struct {
+	iterator.EmptyIterator[[]E]
+	elems []E
+	idxs  []int // indexes of the current permutation, nil before the first one.
+	done  bool
+	curr  []E
+}
+
+func (p *permutationIterator[E]) MoveNext() bool {
+	if p.done {
+		return false
+	}
+	if p.idxs == nil {
+		p.idxs = make([]int, len(p.elems))
+		for i := range p.idxs {
+			p.idxs[i] = i
+		}
+	} else {
+		i := len(p.idxs) - 2
+		for i >= 0 && p.idxs[i] > p.idxs[i+1] {
+			i--
+		}
+		if i < 0 {
+			p.done = true
+			return false
+		}
+		j := len(p.idxs) - 1
+		for p.idxs[j] < p.idxs[i] {
+			j--
+		}
+		p.idxs[i], p.idxs[j] = p.idxs[j], p.idxs[i]
+		for l, r := i+1, len(p.idxs)-1; l < r; l, r = l+1, r-1 {
+			p.idxs[l], p.idxs[r] = p.idxs[r], p.idxs[l]
+		}
+	}
+	p.curr = make([]E, len(p.idxs))
+	for i, idx := range p.idxs {
+		p.curr[i] = p.elems[idx]
+	}
+	return true
+}
+
+func (p *permutationIterator[E]) Current() []E {
+	return p.curr
+}
+
+// endregion
+
+// region powerSetIterable
+
+type powerSetIterable[E any] struct {
+	elems []E
+}
+
+func (p powerSetIterable[E]) Iterator() iterator.Iterator[[]E] {
+	return &powerSetIterator[E]{elems: p.elems}
+}
+
+func (p powerSetIterable[E]) Size() (n uint64, known bool) {
+	if len(p.elems) >= 64 {
+		return 0, false
+	}
+	return 1 << len(p.elems), true
+}
+
+type powerSetIterator[E any] struct {
+	iterator.EmptyIterator[[]E]
+	elems []E
+	k     int
+	inner *combinationIterator[E]
+}
+
+func (p *powerSetIterator[E]) MoveNext() bool {
+	for p.k <= len(p.elems) {
+		if p.inner == nil {
+			p.inner = &combinationIterator[E]{elems: p.elems, k: p.k}
+		}
+		if p.inner.MoveNext() {
+			return true
+		}
+		p.inner = nil
+		p.k++
+	}
+	return false
+}
+
+func (p *powerSetIterator[E]) Current() []E {
+	if p.inner == nil {
+		return nil
+	}
+	return p.inner.Current()
+}
+
+// endregion
@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestProduct(t *testing.T) {
+	stm := Product(Range(0, 3), Of("a", "b"))
+	if slc := stm.Collect(); fmt.Sprint(slc) != "[{0 a} {0 b} {1 a} {1 b} {2 a} {2 b}]" {
+		t.Fatalf("unexpected: %v\n", slc)
+	}
+	if Product(Range(0, 3), Of[string]()).Count() != 0 {
+		t.Fail()
+	}
+	n, known := productIterable[int, string]{a: Range(0, 3), b: Of("a", "b")}.Size()
+	if !known || n != 6 {
+		t.Fatalf("expected: %v, actual: %v\n", 6, n)
+	}
+}
+
+func TestProductN(t *testing.T) {
+	slc := ProductN(Of(0, 1), Of(2, 3), Of(4, 5, 6)).Collect()
+	if len(slc) != 12 {
+		t.Fatalf("expected: %v, actual: %v\n", 12, len(slc))
+	}
+	if fmt.Sprint(slc[0]) != "[0 2 4]" || fmt.Sprint(slc[11]) != "[1 3 6]" {
+		t.Fatalf("unexpected: %v\n", slc)
+	}
+	if ProductN[int]().Count() != 1 {
+		t.Fail()
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	slc := Combinations([]int{0, 1, 2, 3}, 2).Collect()
+	if fmt.Sprint(slc) != "[[0 1] [0 2] [0 3] [1 2] [1 3] [2 3]]" {
+		t.Fatalf("unexpected: %v\n", slc)
+	}
+	if Combinations([]int{0, 1, 2}, 0).Count() != 1 || Combinations([]int{0, 1, 2}, 4).Count() != 0 {
+		t.Fail()
+	}
+	n, known := combinationIterable[int]{elems: make([]int, 60), k: 30}.Size()
+	if !known || n != 118264581564861424 {
+		t.Fatalf("expected: %v, actual: %v\n", uint64(118264581564861424), n)
+	}
+	if _, known = (combinationIterable[int]{elems: make([]int, 100), k: 50}).Size(); known {
+		t.Fail()
+	}
+}
+
+func TestPermutations(t *testing.T) {
+	slc := Permutations([]int{0, 1, 2}).Collect()
+	if fmt.Sprint(slc) != "[[0 1 2] [0 2 1] [1 0 2] [1 2 0] [2 0 1] [2 1 0]]" {
+		t.Fatalf("unexpected: %v\n", slc)
+	}
+	if Permutations([]int{0, 1, 2, 3, 4, 5}).Count() != 720 {
+		t.Fail()
+	}
+	if _, known := (permutationIterable[int]{elems: make([]int, 21)}).Size(); known {
+		t.Fail()
+	}
+}
+
+func TestPowerSet(t *testing.T) {
+	slc := PowerSet([]string{"a", "b", "c"}).Collect()
+	if fmt.Sprint(slc) != "[[] [a] [b] [c] [a b] [a c] [b c] [a b c]]" {
+		t.Fatalf("unexpected: %v\n", slc)
+	}
+	if PowerSet([]int{}).Count() != 1 {
+		t.Fail()
+	}
+	iter := powerSetIterable[int]{elems: []int{0}}.Iterator()
+	if iter.Current() != nil {
+		t.Fatalf("expected nil before MoveNext\n")
+	}
+	for iter.MoveNext() {
+	}
+	if iter.Current() != nil {
+		t.Fatalf("expected nil once exhausted\n")
+	}
+}
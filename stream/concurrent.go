@@ -0,0 +1,180 @@
+package stream
+
+// MapConcurrent is like Map, but runs mapper on up to workers goroutines at once, and still emits the results
+// in the order of their inputs, buffering up to 2*workers results to reorder. It suits slow mappers like lookups
+// over network. A panic of mapper is re-raised on the goroutine running the Stream when its result is due.
+func MapConcurrent[S any, T any](s Stream[S], workers int, mapper func(v S) T) Stream[T] {
+	return mapConcurrent(s, workers, mapper, true)
+}
+
+// MapConcurrentUnordered is like MapConcurrent, but emits results as soon as they are done regardless of the
+// order of their inputs, for maximum throughput.
+func MapConcurrentUnordered[S any, T any](s Stream[S], workers int, mapper func(v S) T) Stream[T] {
+	return mapConcurrent(s, workers, mapper, false)
+}
+
+func mapConcurrent[S any, T any](s Stream[S], workers int, mapper func(v S) T, ordered bool) Stream[T] {
+	if workers <= 0 {
+		panic("non-positive workers of MapConcurrent")
+	}
+	m := reflectBaseMeta(s)
+	if m.Empty() {
+		return newEmptyHeader[T]()
+	}
+	return newOpMapConcurrent(m.Copy(), reflectBaseCurr(s), workers, mapper, ordered)
+}
+
+// region MapConcurrent
+
+type opMapConcurrent[S any, T any] struct {
+	base[T]
+	workers int
+	mapper  func(v S) T
+	ordered bool
+}
+
+func newOpMapConcurrent[S any, T any](meta *meta, upstream pipeline, workers int, mapper func(v S) T,
+	ordered bool) (ret *opMapConcurrent[S, T]) {
+	ret = &opMapConcurrent[S, T]{workers: workers, mapper: mapper, ordered: ordered}
+	// results lag behind inputs, and the rest of them are emitted when closed.
+	ret.base = base[T]{Meta: meta.SetDistinct(false).SetSinkIterable(false), Prev: upstream, Curr: ret}
+	return
+}
+
+func (f *opMapConcurrent[S, T]) WrapSink(down sink) sink {
+	return &mapConcurrentSink[S, T]{
+		baseSink: baseSink{down: down},
+		op:       f,
+		sem:      make(chan struct{}, f.workers),
+		results:  make(chan *mapResult[T], f.workers),
+	}
+}
+
+// mapResult is the result of mapper for one element, or the value it panics with.
+type mapResult[T any] struct {
+	val      T
+	panicked any
+	done     chan struct{} // closed when val or panicked is set, only used when ordered.
+}
+
+// mapConcurrentSink maps elements on one goroutine per element, at most op.workers of which run at once.
+// Goroutines only live as long as mapper runs, so that nothing leaks when the run panics.
+type mapConcurrentSink[S any, T any] struct {
+	baseSink
+	op       *opMapConcurrent[S, T]
+	sem      chan struct{}
+	pending  []*mapResult[T] // results in the order of their inputs, only used when ordered.
+	ready    []*mapResult[T] // results done and to emit in order.
+	results  chan *mapResult[T]
+	inflight int // count of results not received from results yet, only used when unordered.
+}
+
+func (s *mapConcurrentSink[S, T]) start(v S, r *mapResult[T]) {
+	go func() {
+		defer func() {
+			r.panicked = recover()
+			if s.op.ordered {
+				close(r.done)
+			} else {
+				s.results <- r // never blocks, since results holds as many as running goroutines.
+			}
+			<-s.sem
+		}()
+		r.val = s.op.mapper(v)
+	}()
+}
+
+func (s *mapConcurrentSink[S, T]) emit(r *mapResult[T]) {
+	if r.panicked != nil {
+		panic(r.panicked)
+	}
+	if !s.down.Rejecting() {
+		s.down.Accept(r.val)
+	}
+}
+
+// head returns the done channel of the first pending result, or nil if none.
+func (s *mapConcurrentSink[S, T]) head() <-chan struct{} {
+	if len(s.pending) == 0 {
+		return nil
+	}
+	return s.pending[0].done
+}
+
+func (s *mapConcurrentSink[S, T]) popHead() *mapResult[T] {
+	r := s.pending[0]
+	s.pending[0] = nil
+	s.pending = s.pending[1:]
+	return r
+}
+
+func (s *mapConcurrentSink[S, T]) Accept(v any) {
+	r := &mapResult[T]{}
+	if s.op.ordered {
+		r.done = make(chan struct{})
+	}
+	// starts v before emitting anything, so that v is not lost when emitting panics.
+	for acquired := false; !acquired; {
+		select {
+		case s.sem <- struct{}{}:
+			acquired = true
+		case <-s.head():
+			s.ready = append(s.ready, s.popHead())
+		case done := <-s.results:
+			s.inflight--
+			s.ready = append(s.ready, done)
+		}
+	}
+	if s.op.ordered {
+		s.pending = append(s.pending, r)
+	} else {
+		s.inflight++
+	}
+	s.start(v.(S), r)
+	for len(s.pending) > 2*s.op.workers {
+		<-s.head()
+		s.ready = append(s.ready, s.popHead())
+	}
+	// collects results done meanwhile, without blocking.
+	for collecting := true; collecting; {
+		select {
+		case <-s.head():
+			s.ready = append(s.ready, s.popHead())
+		case done := <-s.results:
+			s.inflight--
+			s.ready = append(s.ready, done)
+		default:
+			collecting = false
+		}
+	}
+	for len(s.ready) > 0 {
+		s.emitNext()
+	}
+}
+
+func (s *mapConcurrentSink[S, T]) emitNext() bool {
+	switch {
+	case len(s.ready) > 0:
+		r := s.ready[0]
+		s.ready[0] = nil
+		s.ready = s.ready[1:]
+		s.emit(r)
+	case len(s.pending) > 0:
+		<-s.head()
+		s.emit(s.popHead())
+	case s.inflight > 0:
+		s.inflight--
+		s.emit(<-s.results)
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *mapConcurrentSink[S, T]) Close() {
+	for s.emitNext() {
+	}
+	s.down.Close()
+}
+
+// endregion
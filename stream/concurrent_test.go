@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMapConcurrent(t *testing.T) {
+	var running, peak int32
+	lookup := func(v int) int {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		// later elements finish earlier, to exercise reordering.
+		time.Sleep(time.Duration(10-v%10) * 100 * time.Microsecond)
+		atomic.AddInt32(&running, -1)
+		return v * v
+	}
+	actual := MapConcurrent(Range(0, 100), 4, lookup).Collect()
+	expected := Map(Range(0, 100), func(v int) int { return v * v }).Collect()
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: ordered, expected: %v, actual: %v\n", expected, actual)
+	}
+	if peak > 4 || peak < 2 {
+		t.Fatalf("case: workers, actual peak: %v\n", peak)
+	}
+
+	actual = MapConcurrentUnordered(Range(0, 100), 8, lookup).Collect()
+	sort.Ints(actual)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: unordered, expected: %v, actual: %v\n", expected, actual)
+	}
+
+	actual = MapConcurrent(Range(0, 100), 4, lookup).Limit(3).Collect()
+	if expected = []int{0, 1, 4}; !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: limited, expected: %v, actual: %v\n", expected, actual)
+	}
+	if cnt := MapConcurrentUnordered(Range(0, 10), 3, lookup).Iterator(); cnt == nil {
+		t.Fatalf("case: iterator\n")
+	} else {
+		var n int
+		for cnt.MoveNext() {
+			n++
+		}
+		cnt.Close()
+		if n != 10 {
+			t.Fatalf("case: iterator, expected: %v, actual: %v\n", 10, n)
+		}
+	}
+}
+
+func TestMapConcurrentPanic(t *testing.T) {
+	mapper := func(v int) int {
+		if v%10 == 5 {
+			panic("mapper")
+		}
+		return v
+	}
+	var skipped int
+	actual := WithPanicPolicy(MapConcurrent(Range(0, 20), 3, mapper), PanicSkip, func(*PanicError) {
+		skipped++
+	}).Collect()
+	expected := Range(0, 20).Filter(func(v int) bool { return v%10 != 5 }).Collect()
+	if !reflect.DeepEqual(expected, actual) || skipped != 2 {
+		t.Fatalf("case: skipped, expected: %v, actual: %v, skipped: %v\n", expected, actual, skipped)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != "mapper" {
+				t.Fatalf("case: propagated, actual: %v\n", r)
+			}
+		}()
+		MapConcurrentUnordered(Range(0, 20), 3, mapper).Count()
+	}()
+}
+
+func TestMapConcurrentPanicMetered(t *testing.T) {
+	var skipped int
+	stm := MapConcurrent(Range(0, 20), 3, func(v int) int {
+		if v%10 == 9 {
+			panic("mapper")
+		}
+		return v
+	})
+	stm = WithPanicPolicy(WithMetrics(stm, "metered", NewMemoryMetrics()), PanicSkip, func(*PanicError) {
+		skipped++
+	})
+	if cnt := stm.Count(); cnt != 18 || skipped != 2 {
+		t.Fatalf("expected: %v, actual: %v, skipped: %v\n", 18, cnt, skipped)
+	}
+}
@@ -0,0 +1,49 @@
+package stream
+
+// RunError is the panic value of terminals other than Try and TryXxx, e.g. Collect, whose run of Stream is failed by
+// Err. It wraps Err, so that the cause can still be inspected by errors.Is and errors.As after recovering.
+type RunError struct {
+	Err error
+}
+
+func (e RunError) Error() string {
+	return e.Err.Error()
+}
+
+func (e RunError) Unwrap() error {
+	return e.Err
+}
+
+// fail fails the current run of Stream by err.
+func fail(err error) {
+	panic(RunError{Err: err})
+}
+
+// Try runs f, which usually calls terminal operations of Streams, and returns the error failing any run of
+// them. Runs fail by errors of their sources or stages, e.g. a PanicError under PanicAsError or ctx.Err() of a
+// ContextClock, where terminals other than Try and TryXxx panic with a RunError instead.
+// Other panics are propagated.
+func Try(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			re, ok := r.(RunError)
+			if !ok {
+				panic(r)
+			}
+			err = re.Err
+		}
+	}()
+	f()
+	return nil
+}
+
+// TryCollect is like Stream.Collect, but returns the error failing the run instead of panicking.
+func TryCollect[E any](s Stream[E]) (slc []E, err error) {
+	err = Try(func() { slc = s.Collect() })
+	return
+}
+
+// TryForeach is like Stream.Foreach, but returns the error failing the run instead of panicking.
+func TryForeach[E any](s Stream[E], act func(v E)) error {
+	return Try(func() { s.Foreach(act) })
+}
@@ -0,0 +1,55 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"github.com/not2dim/gostream/iterator"
+	"reflect"
+	"testing"
+)
+
+func TestSourceErr(t *testing.T) {
+	data := []byte{0x01, 0x00, 0x02, 0x00, 0x03}
+	src := Iterable(iterator.BinaryReader[int16](bytes.NewReader(data), binary.LittleEndian))
+	slc, err := TryCollect(src.Filter(func(v int16) bool { return v > 0 }))
+	if slc != nil || !errors.Is(err, iterator.ErrPartialRecord) {
+		t.Fatalf("case: partial, actual: %v, err: %v\n", slc, err)
+	}
+	slc, err = TryCollect(Iterable(iterator.Binary[int16](data[:4], binary.LittleEndian)).Map(func(v int16) int16 {
+		return v * 10
+	}))
+	if !reflect.DeepEqual([]int16{10, 20}, slc) || err != nil {
+		t.Fatalf("case: whole, actual: %v, err: %v\n", slc, err)
+	}
+	// short-circuited before the partial record.
+	first := Iterable(iterator.Binary[int16](data, binary.LittleEndian)).Filter(func(v int16) bool {
+		return v > 0
+	}).First()
+	if !first.OK || first.Val != 1 {
+		t.Fatalf("case: first, actual: %v\n", first)
+	}
+	runes := iterator.StringRuneIterableWithOptions("ab\xffc", iterator.RuneOptions{
+		Invalid: iterator.StopInvalid, ExactSize: true,
+	})
+	if _, err := TryCollect(Iterable(runes)); !errors.Is(err, iterator.ErrInvalidUTF8) {
+		t.Fatalf("case: invalid utf-8, err: %v\n", err)
+	}
+	runes = iterator.StringRuneIterableWithOptions("ab\xffc", iterator.RuneOptions{
+		Invalid: iterator.SkipInvalid, ExactSize: true,
+	})
+	if joined := Joining(Iterable(runes), ",", 0); joined != "a,b,c" {
+		t.Fatalf("case: joining, actual: %v\n", joined)
+	}
+}
+
+func TestRunError(t *testing.T) {
+	src := Iterable(iterator.Binary[int16]([]byte{0x01, 0x00, 0x02}, binary.LittleEndian))
+	defer func() {
+		re, ok := recover().(RunError)
+		if !ok || !errors.Is(re, iterator.ErrPartialRecord) {
+			t.Fatalf("expected: RunError wrapping %v, actual: %v\n", iterator.ErrPartialRecord, re)
+		}
+	}()
+	src.Count()
+}
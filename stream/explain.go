@@ -0,0 +1,154 @@
+package stream
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// StageInfo describes one stage of a Stream pipeline.
+type StageInfo struct {
+	// Kind is the operation of the stage, e.g. "source", "filter", "sortBy".
+	Kind string
+	// MaxSize is the upper bound of the count of elements flowing out of the stage,
+	// and math.MaxUint64 means unbounded.
+	MaxSize uint64
+	// Distinct reports whether elements flowing out of the stage are known to be distinct.
+	Distinct bool
+	// SinkIterable reports whether the stream up to the stage can be iterated by pushing
+	// one source element at a time. Otherwise, Iterator falls back to a goroutine and a channel.
+	SinkIterable bool
+	// Buffering reports whether the stage buffers elements until its upstream is exhausted.
+	Buffering bool
+}
+
+// bufferingStage is implemented by stages, which may buffer elements until their upstream is exhausted.
+type bufferingStage interface {
+	// buffering reports whether the stage buffers elements indeed, which may depend on its upstream.
+	buffering() bool
+}
+
+// Describe returns the stages of the Stream pipeline, ordered from the source to the Stream itself.
+func Describe[E any](s Stream[E]) []StageInfo {
+	var stages []StageInfo
+	for curr := reflectBaseCurr(s); curr != nil; curr = curr.GetUpstream() {
+		m := reflectBaseMeta(curr)
+		b, ok := curr.(bufferingStage)
+		buffering := ok && b.buffering()
+		stages = append(stages, StageInfo{
+			Kind:         stageKind(curr),
+			MaxSize:      m.MaxSize(),
+			Distinct:     m.Distinct(),
+			SinkIterable: m.SinkIterable(),
+			Buffering:    buffering,
+		})
+	}
+	for i, j := 0, len(stages)-1; i < j; i, j = i+1, j-1 {
+		stages[i], stages[j] = stages[j], stages[i]
+	}
+	return stages
+}
+
+// Explain returns a human-readable description of the Stream pipeline, one stage per line,
+// followed by the way its Iterator is built.
+func Explain[E any](s Stream[E]) string {
+	var sb strings.Builder
+	stages := Describe(s)
+	var firstUnsinkable = -1
+	for i, st := range stages {
+		maxSize := "unbounded"
+		if st.MaxSize != math.MaxUint64 {
+			maxSize = fmt.Sprint(st.MaxSize)
+		}
+		fmt.Fprintf(&sb, "#%d %-12s maxSize=%s distinct=%v sinkIterable=%v", i, st.Kind, maxSize, st.Distinct,
+			st.SinkIterable)
+		if st.Buffering {
+			sb.WriteString(" buffering")
+		}
+		sb.WriteString("\n")
+		if !st.SinkIterable && firstUnsinkable < 0 {
+			firstUnsinkable = i
+		}
+	}
+	switch mode := iteratorMode(reflectBaseMeta(s), reflectBaseCurr(s)); mode {
+	case modeChanneled:
+		fmt.Fprintf(&sb, "iterator: %s, since stage #%d %s is not sink-iterable\n", mode, firstUnsinkable,
+			stages[firstUnsinkable].Kind)
+	default:
+		fmt.Fprintf(&sb, "iterator: %s\n", mode)
+	}
+	return sb.String()
+}
+
+// kindedStage is implemented by stages whose kind is not derived from their type names.
+type kindedStage interface {
+	kind() string
+}
+
+// stageKind returns the operation name of the pipeline, derived from its type name.
+func stageKind(p pipeline) string {
+	if k, ok := p.(kindedStage); ok {
+		return k.kind()
+	}
+	name := reflect.TypeOf(p).Elem().Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	switch name {
+	case "header":
+		return "source"
+	case "emptyHeader":
+		return "empty"
+	}
+	name = strings.TrimPrefix(name, "op")
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// segmentStages indexes the stages of a segment of the pipeline in a run, where stages wrapped by Async
+// run in separate segments.
+type segmentStages struct {
+	kinds  []string // kinds of stages, counted from the source of the segment.
+	offset int      // index of the source of the segment, counted from the source of the pipeline.
+}
+
+// newSegmentStages returns the segmentStages of pipelines, ordered from the terminal to the source as in process.
+func newSegmentStages(pipelines []pipeline, offset int) segmentStages {
+	n := len(pipelines)
+	s := segmentStages{kinds: make([]string, n), offset: offset}
+	for i, p := range pipelines {
+		s.kinds[n-1-i] = stageKind(p)
+	}
+	return s
+}
+
+// index returns the index and the kind of the stage of the segment, counted from the source of the pipeline.
+func (s segmentStages) index(stage int) (idx int, kind string) {
+	return s.offset + stage, s.kinds[stage]
+}
+
+type iterMode int
+
+const (
+	modeEmpty     iterMode = iota // no elements at all.
+	modeSource                    // the source iterator itself.
+	modeSink                      // pushes one source element at a time through the wrapped sinks.
+	modeChanneled                 // runs the pipeline in a goroutine and receives elements from a channel.
+)
+
+func (m iterMode) String() string {
+	return [...]string{"empty", "source", "sink", "channeled"}[m]
+}
+
+// iteratorMode returns the way Iterator is built for the stage curr of the given meta.
+func iteratorMode(m *meta, curr pipeline) iterMode {
+	switch {
+	case m.Empty():
+		return modeEmpty
+	case curr.GetSource() != nil:
+		return modeSource
+	case m.SinkIterable():
+		return modeSink
+	}
+	return modeChanneled
+}
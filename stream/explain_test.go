@@ -0,0 +1,49 @@
+package stream
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	stm := Range(0, 100).Filter(func(v int) bool { return v%2 == 0 }).Limit(10).SortBy(CmpRealNum[int]).Distinct()
+	stages := Describe(stm)
+	expected := []StageInfo{
+		{Kind: "source", MaxSize: math.MaxUint64, SinkIterable: true},
+		{Kind: "filter", MaxSize: math.MaxUint64, SinkIterable: true},
+		{Kind: "limit", MaxSize: 10, SinkIterable: true},
+		{Kind: "sortBy", MaxSize: 10, Buffering: true},
+		{Kind: "distinct", MaxSize: 10, Distinct: true, Buffering: true},
+	}
+	if len(stages) != len(expected) {
+		t.Fatalf("expected: %+v, actual: %+v\n", expected, stages)
+	}
+	for i := range expected {
+		if stages[i] != expected[i] {
+			t.Fatalf("stage: %v, expected: %+v, actual: %+v\n", i, expected[i], stages[i])
+		}
+	}
+	if stages := Describe(Of(0, 1, 2).Skip(5)); len(stages) != 1 || stages[0].Kind != "empty" {
+		t.Fatalf("unexpected: %+v\n", stages)
+	}
+	stages = Describe(WithTracer(WithMetrics(Of(0), "", nil), "", nil, 0))
+	if len(stages) != 3 || stages[1].Kind != "metrics" || stages[2].Kind != "trace" {
+		t.Fatalf("unexpected: %+v\n", stages)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	explained := Explain(Map(Of(0, 1, 2), func(v int) string { return "" }))
+	if !strings.Contains(explained, "#1 mapToAny") || !strings.HasSuffix(explained, "iterator: sink\n") {
+		t.Fatalf("unexpected: %v\n", explained)
+	}
+	explained = Explain(Of(0, 1, 2).FlatMap(func(v int) Stream[int] { return Of(v) }).Limit(5))
+	if !strings.HasSuffix(explained, "iterator: channeled, since stage #1 flatMap is not sink-iterable\n") {
+		t.Fatalf("unexpected: %v\n", explained)
+	}
+	if !strings.HasSuffix(Explain(Of(0, 1, 2)), "iterator: source\n") {
+		t.Fail()
+	}
+	t.Log("\n" + explained)
+}
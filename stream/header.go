@@ -30,9 +30,10 @@ func (h *emptyHeader[E]) GetSource() iterator.Iterable[any] {
 }
 
 func newEmptyHeader[E any]() *emptyHeader[E] {
-	return &emptyHeader[E]{
-		base[E]{
-			Meta: defaultMeta.Copy().SetDistinct(true).SetMaxSize(0),
-		},
+	ret := &emptyHeader[E]{}
+	ret.base = base[E]{
+		Meta: defaultMeta.Copy().SetDistinct(true).SetMaxSize(0),
 	}
+	ret.Curr = ret
+	return ret
 }
@@ -0,0 +1,66 @@
+package stream
+
+import (
+	"math"
+	"sort"
+)
+
+// Histogram counts numeric elements by buckets. Counts[i] counts elements v with Bounds[i-1] < v <= Bounds[i],
+// and the last bucket Counts[len(Bounds)] counts elements greater than all bounds.
+type Histogram struct {
+	Bounds []float64
+	Counts []uint64
+	Count  uint64
+	Sum    float64
+}
+
+// NewHistogram returns a new empty Histogram of the given ascending upper bounds.
+func NewHistogram(bounds []float64) *Histogram {
+	if !sort.Float64sAreSorted(bounds) {
+		panic("histogram bounds must be sorted in ascending order")
+	}
+	return &Histogram{Bounds: bounds, Counts: make([]uint64, len(bounds)+1)}
+}
+
+// Add adds v into its bucket.
+func (h *Histogram) Add(v float64) {
+	h.Counts[sort.SearchFloat64s(h.Bounds, v)]++
+	h.Count++
+	h.Sum += v
+}
+
+// LinearBuckets returns n upper bounds start, start+width, ..., start+(n-1)*width.
+func LinearBuckets(start, width float64, n int) []float64 {
+	bounds := make([]float64, n)
+	for i := range bounds {
+		bounds[i] = start + float64(i)*width
+	}
+	return bounds
+}
+
+// ExponentialBuckets returns n upper bounds start, start*factor, ..., start*factor^(n-1).
+func ExponentialBuckets(start, factor float64, n int) []float64 {
+	if start <= 0 || factor <= 1 {
+		panic("exponential buckets require start > 0 and factor > 1")
+	}
+	bounds := make([]float64, n)
+	for i := range bounds {
+		bounds[i] = start * math.Pow(factor, float64(i))
+	}
+	return bounds
+}
+
+// ToHistogram collects elements of the Stream[N] into a Histogram of the given ascending upper bounds,
+// which are usually built by LinearBuckets or ExponentialBuckets.
+func ToHistogram[N realNum](s Stream[N], bounds []float64) *Histogram {
+	return Collect(s,
+		func(size uint64, known bool) *Histogram {
+			return NewHistogram(bounds)
+		},
+		func(b *Histogram, a N) *Histogram {
+			b.Add(float64(a))
+			return b
+		},
+		Identity[*Histogram],
+	)
+}
@@ -0,0 +1,25 @@
+package stream
+
+import (
+	"testing"
+)
+
+func TestToHistogram(t *testing.T) {
+	h := ToHistogram(Range(0, 100), LinearBuckets(9, 10, 5))
+	expected := []uint64{10, 10, 10, 10, 10, 50}
+	for i := range expected {
+		if h.Counts[i] != expected[i] {
+			t.Fatalf("expected: %v, actual: %v\n", expected, h.Counts)
+		}
+	}
+	if h.Count != 100 || h.Sum != 4950 {
+		t.Fatalf("unexpected: %+v\n", h)
+	}
+	h = ToHistogram(Of(0.5, 1, 3, 4, 100), ExponentialBuckets(1, 2, 3))
+	expected = []uint64{2, 0, 2, 1}
+	for i := range expected {
+		if h.Counts[i] != expected[i] {
+			t.Fatalf("expected: %v, actual: %v\n", expected, h.Counts)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package stream
+
+import "github.com/not2dim/gostream/iterator"
+
+// Interleave returns a new Stream[E] taking one element from each input Stream in turn.
+// Exhausted inputs are dropped, and the remaining ones keep being interleaved until all of them are exhausted.
+func Interleave[E any](ss ...Stream[E]) Stream[E] {
+	weights := make([]uint64, len(ss))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return newInterleave(ss, weights, false)
+}
+
+// InterleaveWeighted is like Interleave, but takes up to weights[i] elements from ss[i] in each turn.
+// An input with weight 0 never contributes any element.
+func InterleaveWeighted[E any](weights []uint64, ss ...Stream[E]) Stream[E] {
+	if len(weights) != len(ss) {
+		panic("the count of weights mismatches the count of streams")
+	}
+	return newInterleave(ss, weights, false)
+}
+
+// Alternate returns a new Stream[E] taking one element from each input Stream in turn,
+// and stops as soon as the input Stream in turn is exhausted.
+func Alternate[E any](ss ...Stream[E]) Stream[E] {
+	weights := make([]uint64, len(ss))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return newInterleave(ss, weights, true)
+}
+
+func newInterleave[E any](ss []Stream[E], weights []uint64, strict bool) Stream[E] {
+	var maxSize uint64
+	for i, s := range ss {
+		if weights[i] == 0 {
+			continue
+		}
+		maxSize = addSize(maxSize, reflectBaseMeta(s).MaxSize())
+	}
+	if maxSize == 0 {
+		return newEmptyHeader[E]()
+	}
+	return newHeader[E](
+		defaultMeta.Copy().SetMaxSize(maxSize),
+		interleaveIterable[E]{ss: ss, weights: weights, strict: strict},
+	)
+}
+
+// region interleaveIterable
+
+type interleaveIterable[E any] struct {
+	ss      []Stream[E]
+	weights []uint64
+	strict  bool // stops once the input in turn is exhausted.
+}
+
+func (it interleaveIterable[E]) Iterator() iterator.Iterator[E] {
+	live := make([]int, 0, len(it.ss))
+	for i, w := range it.weights {
+		if w > 0 {
+			live = append(live, i)
+		}
+	}
+	return &interleaveIterator[E]{
+		ss:      it.ss,
+		weights: it.weights,
+		strict:  it.strict,
+		iters:   make([]iterator.Iterator[E], len(it.ss)),
+		live:    live,
+	}
+}
+
+func (it interleaveIterable[E]) Size() (n uint64, known bool) {
+	return 0, false
+}
+
+type interleaveIterator[E any] struct {
+	ss      []Stream[E]
+	weights []uint64
+	strict  bool
+	iters   []iterator.Iterator[E] // inner iterators are opened lazily on their first turn.
+	live    []int                  // indexes of the inputs not exhausted yet, in round-robin order.
+	pos     int                    // position in live of the input in turn.
+	taken   uint64                 // count of elements taken from the input in turn.
+	curr    E
+}
+
+func (it *interleaveIterator[E]) MoveNext() bool {
+	for len(it.live) > 0 {
+		if it.pos >= len(it.live) {
+			it.pos = 0
+		}
+		idx := it.live[it.pos]
+		if it.taken >= it.weights[idx] {
+			it.pos++
+			it.taken = 0
+			continue
+		}
+		if it.iters[idx] == nil {
+			it.iters[idx] = it.ss[idx].Iterator()
+		}
+		if it.iters[idx].MoveNext() {
+			it.curr = it.iters[idx].Current()
+			it.taken++
+			return true
+		}
+		it.iters[idx].Close()
+		it.iters[idx] = nil
+		if it.strict {
+			it.live = nil
+			break
+		}
+		it.live = append(it.live[:it.pos], it.live[it.pos+1:]...)
+		it.taken = 0
+	}
+	return false
+}
+
+func (it *interleaveIterator[E]) Current() E {
+	return it.curr
+}
+
+func (it *interleaveIterator[E]) Close() {
+	for i, iter := range it.iters {
+		if iter != nil {
+			iter.Close()
+			it.iters[i] = nil
+		}
+	}
+	it.live = nil
+}
+
+// endregion
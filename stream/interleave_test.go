@@ -0,0 +1,66 @@
+package stream
+
+import (
+	"testing"
+)
+
+func TestInterleave(t *testing.T) {
+	slc := Interleave(
+		Of(0, 3, 6, 8),
+		Of(1, 4),
+		Of(2, 5, 7, 9),
+	).Collect()
+	if len(slc) != 10 {
+		t.Fatalf("expected: %v, actual: %v\n", 10, len(slc))
+	}
+	for idx, v := range slc {
+		if idx != v {
+			t.Fatalf("idx: %v, expected: %v, actual: %v\n", idx, idx, v)
+		}
+	}
+	if Interleave[int]().Count() != 0 {
+		t.Fail()
+	}
+}
+
+func TestInterleaveWeighted(t *testing.T) {
+	expected := []string{"a", "a", "b", "a", "a", "b", "a", "b", "b"}
+	slc := InterleaveWeighted([]uint64{2, 1, 0},
+		Of("a", "a", "a", "a", "a"),
+		Of("b", "b", "b", "b"),
+		Of("c", "c"),
+	).Collect()
+	if len(slc) != len(expected) {
+		t.Fatalf("expected: %v, actual: %v\n", expected, slc)
+	}
+	for i := range expected {
+		if slc[i] != expected[i] {
+			t.Fatalf("expected: %v, actual: %v\n", expected, slc)
+		}
+	}
+}
+
+func TestInterleaveLazy(t *testing.T) {
+	var pulled int
+	first := Range(0, 100).Peek(func(int) { pulled++ })
+	second := Range(100, 200).Peek(func(int) { pulled++ })
+	slc := Interleave(first, second).Limit(4).Collect()
+	if len(slc) != 4 || slc[0] != 0 || slc[1] != 100 || slc[2] != 1 || slc[3] != 101 {
+		t.Fatalf("unexpected: %v\n", slc)
+	}
+	if pulled > 5 {
+		t.Fatalf("expected at most 5 pulled elements, actual: %v\n", pulled)
+	}
+}
+
+func TestAlternate(t *testing.T) {
+	slc := Alternate(Of(0, 2, 4, 6), Of(1, 3)).Collect()
+	if len(slc) != 5 {
+		t.Fatalf("expected: %v, actual: %v\n", 5, len(slc))
+	}
+	for idx, v := range slc {
+		if idx != v {
+			t.Fatalf("idx: %v, expected: %v, actual: %v\n", idx, idx, v)
+		}
+	}
+}
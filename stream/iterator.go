@@ -1,6 +1,9 @@
 package stream
 
-import "github.com/not2dim/gostream/iterator"
+import (
+	"github.com/not2dim/gostream/iterator"
+	"math"
+)
 
 // region anyIterable
 
@@ -34,14 +37,25 @@ type anyIterator[E any] struct {
 	inner iterator.Iterator[E]
 }
 
+// MoveNext fails the run by the error stopping inner if inner is an iterator.ErrIterator.
 func (i anyIterator[E]) MoveNext() bool {
-	return i.inner.MoveNext()
+	if i.inner.MoveNext() {
+		return true
+	}
+	if err := iterator.Err(i.inner); err != nil {
+		fail(err)
+	}
+	return false
 }
 
 func (i anyIterator[E]) Current() any {
 	return i.inner.Current()
 }
 
+func (i anyIterator[E]) Close() {
+	i.inner.Close()
+}
+
 func wrapIterator[E any](iter iterator.Iterator[E]) iterator.Iterator[any] {
 	return anyIterator[E]{inner: iter}
 }
@@ -60,42 +74,166 @@ func unwrapIterator[E any](anyIter iterator.Iterator[any]) iterator.Iterator[E]
 
 type rangeIterable[E integer | uinteger] struct {
 	from, to E
+	desc     bool // iterates from to-1 down to from.
 }
 
 func newRangeIterable[E integer | uinteger](from, to E) iterator.Iterable[E] {
 	if to < from {
 		to = from
 	}
-	return rangeIterable[E]{from, to}
+	return rangeIterable[E]{from: from, to: to}
 }
 
 func (r rangeIterable[E]) Iterator() iterator.Iterator[E] {
-	return &rangeIterator[E]{
+	size, _ := r.Size()
+	ret := &rangeIterator[E]{
 		from: r.from,
 		to:   r.to,
-		curr: r.from - 1,
+		desc: r.desc,
+		n:    int(Min(size, math.MaxInt)),
+		idx:  -1,
 	}
+	if size > math.MaxInt {
+		// hides MovePrev and Seek, which index elements by int.
+		return struct{ iterator.Iterator[E] }{ret}
+	}
+	return ret
 }
 
 func (r rangeIterable[E]) Size() (n uint64, known bool) {
-	return uint64(r.to - r.from), true
+	// subtracts in uint64 instead of E, which may overflow, e.g. Range[int8](-100, 100).
+	return uint64(r.to) - uint64(r.from), true
+}
+
+func (r rangeIterable[E]) Reverse() iterator.Iterable[E] {
+	return rangeIterable[E]{from: r.from, to: r.to, desc: !r.desc}
 }
 
 type rangeIterator[E integer | uinteger] struct {
 	iterator.EmptyIterator[E]
-	from, to, curr E
+	from, to E
+	desc     bool
+	n        int
+	idx      int // index of the current element, -1 before the first one.
 }
 
 func (r *rangeIterator[E]) MoveNext() bool {
-	if r.curr+1 >= r.to {
+	if r.idx+1 >= r.n {
+		r.idx = r.n
 		return false
 	}
-	r.curr++
+	r.idx++
+	return true
+}
+
+func (r *rangeIterator[E]) MovePrev() bool {
+	if r.idx-1 < 0 {
+		r.idx = -1
+		return false
+	}
+	r.idx--
 	return true
 }
 
 func (r *rangeIterator[E]) Current() E {
-	return r.curr
+	if r.desc {
+		return r.to - 1 - E(r.idx)
+	}
+	return r.from + E(r.idx)
+}
+
+func (r *rangeIterator[E]) Seek(pos int) {
+	r.idx = Max(Min(pos, r.n), 0) - 1
+}
+
+func (r *rangeIterator[E]) Pos() int {
+	return Min(r.idx+1, r.n)
+}
+
+func (r *rangeIterator[E]) Len() int {
+	return r.n
+}
+
+// endregion
+
+// region chanIterable
+
+type chanIterable[E any] struct {
+	ch <-chan E
+}
+
+func (c chanIterable[E]) Iterator() iterator.Iterator[E] {
+	return &chanIterator[E]{ch: c.ch}
+}
+
+func (c chanIterable[E]) Size() (n uint64, known bool) {
+	return 0, false
+}
+
+type chanIterator[E any] struct {
+	iterator.EmptyIterator[E]
+	ch   <-chan E
+	curr E
+}
+
+func (c *chanIterator[E]) MoveNext() bool {
+	v, ok := <-c.ch
+	c.curr = v
+	return ok
+}
+
+func (c *chanIterator[E]) Current() E {
+	return c.curr
+}
+
+// endregion
+
+// region seekIterable
+
+// skippedIterable skips the first n elements of inner, by seeking if its Iterator is a Seeker.
+type skippedIterable[E any] struct {
+	inner iterator.Iterable[E]
+	n     uint64
+}
+
+func (s skippedIterable[E]) Iterator() iterator.Iterator[E] {
+	iter := s.inner.Iterator()
+	if sk, ok := iter.(iterator.Seeker); ok {
+		if left := uint64(sk.Len() - sk.Pos()); s.n < left {
+			sk.Seek(sk.Pos() + int(s.n))
+		} else {
+			sk.Seek(sk.Len())
+		}
+		return iter
+	}
+	for i := uint64(0); i < s.n && iter.MoveNext(); i++ {
+	}
+	return iter
+}
+
+func (s skippedIterable[E]) Size() (n uint64, known bool) {
+	n, known = s.inner.Size()
+	if n < s.n {
+		return 0, known
+	}
+	return n - s.n, known
+}
+
+// lastIterable seeks right before the last element of inner if its Iterator is a Seeker.
+type lastIterable[E any] struct {
+	inner iterator.Iterable[E]
+}
+
+func (l lastIterable[E]) Iterator() iterator.Iterator[E] {
+	iter := l.inner.Iterator()
+	if sk, ok := iter.(iterator.Seeker); ok {
+		sk.Seek(sk.Len() - 1)
+	}
+	return iter
+}
+
+func (l lastIterable[E]) Size() (n uint64, known bool) {
+	return l.inner.Size()
 }
 
 // endregion
@@ -8,6 +8,11 @@ type meta struct {
 	maxSize      uint64
 	distinct     bool
 	sinkIterable bool
+	metrics      *metricsConfig
+	trace        *traceConfig
+	reuse        ReusePolicy
+	panic        *panicConfig
+	hooked       bool
 }
 
 var defaultMeta *meta = nil
@@ -62,6 +67,74 @@ func (m *meta) SetSinkIterable(able bool) *meta {
 	return m
 }
 
+func (m *meta) Metrics() *metricsConfig {
+	if m == nil {
+		return nil
+	}
+	return m.metrics
+}
+
+func (m *meta) SetMetrics(metrics *metricsConfig) *meta {
+	m.metrics = metrics
+	return m
+}
+
+func (m *meta) Trace() *traceConfig {
+	if m == nil {
+		return nil
+	}
+	return m.trace
+}
+
+func (m *meta) SetTrace(trace *traceConfig) *meta {
+	m.trace = trace
+	return m
+}
+
+// ReusePolicy returns the ReusePolicy in effect, resolving ReuseDefault to the build-time default.
+func (m *meta) ReusePolicy() ReusePolicy {
+	if m == nil || m.reuse == ReuseDefault {
+		return defaultReusePolicy
+	}
+	return m.reuse
+}
+
+func (m *meta) SetReusePolicy(reuse ReusePolicy) *meta {
+	m.reuse = reuse
+	return m
+}
+
+func (m *meta) Panic() *panicConfig {
+	if m == nil {
+		return nil
+	}
+	return m.panic
+}
+
+func (m *meta) SetPanic(conf *panicConfig) *meta {
+	m.panic = conf
+	return m
+}
+
+// Hooked reports whether the pipeline holds any OnClose hooks up to the stage.
+func (m *meta) Hooked() bool {
+	if m == nil {
+		return false
+	}
+	return m.hooked
+}
+
+func (m *meta) SetHooked(hooked bool) *meta {
+	m.hooked = hooked
+	return m
+}
+
+// Empty reports whether the stage is known to yield no elements and has no OnClose hooks to run, so that
+// running it can be skipped altogether.
+func (m *meta) Empty() bool {
+	return m.MaxSize() == 0 && !m.Hooked()
+}
+
 func (m *meta) Copy() *meta {
 	if m == nil {
 		return &meta{
@@ -73,3 +146,33 @@ func (m *meta) Copy() *meta {
 	var cp = *m
 	return &cp
 }
+
+// region Configure
+
+// opConfigure is a stage passing elements through, whose meta configures runs of the whole pipeline,
+// e.g. by WithPanicPolicy or WithMetrics.
+type opConfigure[E any] struct {
+	base[E]
+	kindName string
+}
+
+// configure returns a new Stream[E] identical to s, except that its meta is set by set, or s itself if it's empty.
+func configure[E any](s Stream[E], kind string, set func(m *meta) *meta) Stream[E] {
+	m := reflectBaseMeta(s)
+	if m.Empty() {
+		return s
+	}
+	ret := &opConfigure[E]{kindName: kind}
+	ret.base = base[E]{Meta: set(m.Copy()), Prev: reflectBaseCurr(s), Curr: ret}
+	return ret
+}
+
+func (f *opConfigure[E]) WrapSink(down sink) sink {
+	return down
+}
+
+func (f *opConfigure[E]) kind() string {
+	return f.kindName
+}
+
+// endregion
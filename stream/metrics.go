@@ -0,0 +1,228 @@
+package stream
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of metrics reported to a MetricsRegistry. All stage metrics are labeled by "pipeline", "stage" and "kind",
+// where "stage" is the index of the stage counted from the source, and "kind" is the same as StageInfo.Kind.
+const (
+	// MetricPipelineRuns counts finished runs of a pipeline, labeled by "pipeline" only.
+	MetricPipelineRuns = "gostream_pipeline_runs_total"
+	// MetricStageElementsIn counts elements flowing into a stage.
+	MetricStageElementsIn = "gostream_stage_elements_in_total"
+	// MetricStageElementsOut counts elements flowing out of a stage.
+	MetricStageElementsOut = "gostream_stage_elements_out_total"
+	// MetricStageSeconds counts seconds spent in a stage, including user funcs but excluding its downstream.
+	MetricStageSeconds = "gostream_stage_seconds_total"
+	// MetricStageBufferHighWater is the largest count of elements buffered by a buffering stage in the last run.
+	MetricStageBufferHighWater = "gostream_stage_buffer_high_water"
+)
+
+// Labels are the label names and values of a metric.
+type Labels map[string]string
+
+// MetricsRegistry receives metrics of instrumented pipelines. It's usually an adapter to an exporter
+// like Prometheus, whose counters and gauges are identified by name and labels.
+// Metrics of a run are reported at once when the run finishes, so implementations must be safe for concurrent use.
+type MetricsRegistry interface {
+	// AddCounter adds delta to the counter.
+	AddCounter(name string, labels Labels, delta float64)
+	// SetGauge sets the gauge to value.
+	SetGauge(name string, labels Labels, value float64)
+}
+
+type metricsConfig struct {
+	name string
+	reg  MetricsRegistry
+}
+
+// WithMetrics returns a new Stream[E] identical to s, except that every run of its pipeline, including stages
+// both before and after WithMetrics, reports metrics named name to reg.
+func WithMetrics[E any](s Stream[E], name string, reg MetricsRegistry) Stream[E] {
+	conf := &metricsConfig{name: name, reg: reg}
+	return configure(s, "metrics", func(m *meta) *meta { return m.SetMetrics(conf) })
+}
+
+// bufferedSink is implemented by sinks of buffering stages.
+type bufferedSink interface {
+	bufferLen() int
+}
+
+// stageMeter measures one stage in a run.
+type stageMeter struct {
+	stage     int
+	kind      string
+	in        uint64
+	elapsed   time.Duration // including the time spent in its downstream.
+	highWater int
+	buffering bool
+}
+
+// metricsRun measures all stages in a run of a segment of the pipeline, where stages[0] is the source of the
+// segment, and offset is its index counted from the source of the pipeline.
+type metricsRun struct {
+	conf   *metricsConfig
+	stages []*stageMeter
+	offset int
+}
+
+func newMetricsRun(conf *metricsConfig, stages segmentStages) *metricsRun {
+	r := &metricsRun{conf: conf, stages: make([]*stageMeter, len(stages.kinds)), offset: stages.offset}
+	for i := range r.stages {
+		m := &stageMeter{}
+		m.stage, m.kind = stages.index(i)
+		r.stages[i] = m
+	}
+	return r
+}
+
+// wrap returns a meteredSink measuring the wrapped sink of the stage.
+func (r *metricsRun) wrap(stage int, wrapped sink) sink {
+	m := r.stages[stage]
+	buf, _ := wrapped.(bufferedSink)
+	m.buffering = buf != nil
+	ret := &meteredSink{inner: wrapped, meter: m, buf: buf}
+	if stage == 1 {
+		ret.run = r
+	}
+	return ret
+}
+
+func (r *metricsRun) flush() {
+	reg := r.conf.reg
+	if r.offset == 0 {
+		// counts the run once, even though segments split by Async flush separately.
+		reg.AddCounter(MetricPipelineRuns, Labels{"pipeline": r.conf.name}, 1)
+	}
+	for i, m := range r.stages {
+		labels := Labels{"pipeline": r.conf.name, "stage": strconv.Itoa(m.stage), "kind": m.kind}
+		if i > 0 {
+			reg.AddCounter(MetricStageElementsIn, labels, float64(m.in))
+		}
+		if i+1 < len(r.stages) {
+			reg.AddCounter(MetricStageElementsOut, labels, float64(r.stages[i+1].in))
+		}
+		if i > 0 {
+			elapsed := m.elapsed
+			if i+1 < len(r.stages) {
+				elapsed -= r.stages[i+1].elapsed
+			}
+			reg.AddCounter(MetricStageSeconds, labels, elapsed.Seconds())
+		}
+		if m.buffering {
+			reg.SetGauge(MetricStageBufferHighWater, labels, float64(m.highWater))
+		}
+	}
+}
+
+// meteredSink measures the wrapped sink of a stage, and the outermost one flushes the run when closed.
+type meteredSink struct {
+	inner sink
+	meter *stageMeter
+	buf   bufferedSink
+	run   *metricsRun
+}
+
+func (s *meteredSink) Begin(size uint64, known bool) {
+	start := time.Now()
+	s.inner.Begin(size, known)
+	s.meter.elapsed += time.Since(start)
+}
+
+func (s *meteredSink) Accept(v any) {
+	s.meter.in++
+	start := time.Now()
+	s.inner.Accept(v)
+	s.meter.elapsed += time.Since(start)
+	if s.buf != nil {
+		s.meter.highWater = Max(s.meter.highWater, s.buf.bufferLen())
+	}
+}
+
+func (s *meteredSink) unwrap() sink {
+	return s.inner
+}
+
+func (s *meteredSink) Rejecting() bool {
+	return s.inner.Rejecting()
+}
+
+func (s *meteredSink) Close() {
+	start := time.Now()
+	s.inner.Close()
+	s.meter.elapsed += time.Since(start)
+	if s.run != nil {
+		s.run.flush()
+	}
+}
+
+// region MemoryMetrics
+
+// MemoryMetrics is a MetricsRegistry keeping all metrics in memory.
+type MemoryMetrics struct {
+	mu       sync.Mutex
+	counters map[string]float64
+	gauges   map[string]float64
+}
+
+// NewMemoryMetrics returns a new empty MemoryMetrics.
+func NewMemoryMetrics() *MemoryMetrics {
+	return &MemoryMetrics{
+		counters: make(map[string]float64),
+		gauges:   make(map[string]float64),
+	}
+}
+
+func metricKey(name string, labels Labels) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteString("{")
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(k)
+		sb.WriteString("=")
+		sb.WriteString(strconv.Quote(labels[k]))
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+func (m *MemoryMetrics) AddCounter(name string, labels Labels, delta float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[metricKey(name, labels)] += delta
+}
+
+func (m *MemoryMetrics) SetGauge(name string, labels Labels, value float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gauges[metricKey(name, labels)] = value
+}
+
+// Counter returns the value of the counter, or 0 if it does not exist.
+func (m *MemoryMetrics) Counter(name string, labels Labels) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counters[metricKey(name, labels)]
+}
+
+// Gauge returns the value of the gauge, or 0 if it does not exist.
+func (m *MemoryMetrics) Gauge(name string, labels Labels) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.gauges[metricKey(name, labels)]
+}
+
+// endregion
@@ -0,0 +1,64 @@
+package stream
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithMetrics(t *testing.T) {
+	reg := NewMemoryMetrics()
+	for i := 0; i < 2; i++ {
+		stm := WithMetrics(Range(0, 100), "test", reg).
+			Filter(func(v int) bool { return v%2 == 0 }).
+			Peek(func(int) { time.Sleep(100 * time.Microsecond) }).
+			SortBy(CmpRealNum[int]).
+			Limit(10)
+		if cnt := len(stm.Collect()); cnt != 10 {
+			t.Fatalf("expected: %v, actual: %v\n", 10, cnt)
+		}
+	}
+	labels := func(stage, kind string) Labels {
+		return Labels{"pipeline": "test", "stage": stage, "kind": kind}
+	}
+	if v := reg.Counter(MetricPipelineRuns, Labels{"pipeline": "test"}); v != 2 {
+		t.Fatalf("expected: %v, actual: %v\n", 2, v)
+	}
+	for _, tc := range []struct {
+		name     string
+		labels   Labels
+		expected float64
+	}{
+		{MetricStageElementsOut, labels("0", "source"), 200},
+		{MetricStageElementsIn, labels("2", "filter"), 200},
+		{MetricStageElementsOut, labels("2", "filter"), 100},
+		{MetricStageElementsIn, labels("4", "sortBy"), 100},
+		{MetricStageElementsOut, labels("5", "limit"), 20},
+		{MetricStageElementsIn, labels("6", "foreach"), 20},
+	} {
+		if v := reg.Counter(tc.name, tc.labels); v != tc.expected {
+			t.Fatalf("metric: %v%v, expected: %v, actual: %v\n", tc.name, tc.labels, tc.expected, v)
+		}
+	}
+	if v := reg.Gauge(MetricStageBufferHighWater, labels("4", "sortBy")); v != 50 {
+		t.Fatalf("expected: %v, actual: %v\n", 50, v)
+	}
+	peek := reg.Counter(MetricStageSeconds, labels("3", "peek"))
+	filter := reg.Counter(MetricStageSeconds, labels("2", "filter"))
+	if peek < 0.01 || filter > peek {
+		t.Fatalf("unexpected seconds, peek: %v, filter: %v\n", peek, filter)
+	}
+}
+
+func TestWithMetricsIterator(t *testing.T) {
+	reg := NewMemoryMetrics()
+	iter := WithMetrics(Of(0, 1, 2, 3), "iter", reg).Map(func(v int) int { return v * 2 }).Iterator()
+	for iter.MoveNext() {
+	}
+	iter.Close()
+	if v := reg.Counter(MetricPipelineRuns, Labels{"pipeline": "iter"}); v != 1 {
+		t.Fatalf("expected: %v, actual: %v\n", 1, v)
+	}
+	if v := reg.Counter(MetricStageElementsIn, Labels{"pipeline": "iter", "stage": "2", "kind": "map"}); v != 4 {
+		t.Fatalf("expected: %v, actual: %v\n", 4, v)
+	}
+}
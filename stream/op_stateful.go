@@ -1,19 +1,23 @@
 package stream
 
 import (
+	"container/heap"
+	"github.com/not2dim/gostream/iterator"
+	"math"
+	"math/rand"
 	"sort"
 )
 
 // region Skip
 
 type opSkip[E any] struct {
-	*base[E]
+	base[E]
 	n uint64
 }
 
 func newOpSkip[E any](meta *meta, upstream pipeline, n uint64) (ret *opSkip[E]) {
 	ret = &opSkip[E]{n: n}
-	ret.base = &base[E]{Meta: meta.DecrSize(n), Prev: upstream, Curr: ret}
+	ret.base = base[E]{Meta: meta.DecrSize(n), Prev: upstream, Curr: ret}
 	return
 }
 
@@ -25,7 +29,7 @@ type skipSink struct {
 
 func (s *skipSink) Begin(size uint64, known bool) {
 	if known {
-		s.down.Begin(Max(size-s.n, 0), true)
+		s.down.Begin(size-Min(size, s.n), true)
 	} else {
 		s.down.Begin(0, false)
 	}
@@ -43,6 +47,14 @@ func (f *opSkip[E]) WrapSink(down sink) sink {
 	return &skipSink{baseSink: baseSink{down: down}, n: f.n, i: 0}
 }
 
+func (f *opSkip[E]) applySource(src pipeline) (iterator.Iterable[any], bool) {
+	h, ok := src.(*header[E])
+	if !ok {
+		return nil, false
+	}
+	return wrapIterable[E](skippedIterable[E]{inner: h.src, n: f.n}), true
+}
+
 // endregion
 
 // region Limit
@@ -156,6 +168,10 @@ func (s *distinctSink) Accept(v any) {
 	s.m[v] = struct{}{}
 }
 
+func (s *distinctSink) bufferLen() int {
+	return len(s.m)
+}
+
 func (s *distinctSink) Close() {
 	s.down.Begin(uint64(len(s.m)), true)
 	for v := range s.m {
@@ -171,6 +187,10 @@ func (f *opDistinct[E]) WrapSink(down sink) sink {
 	return &distinctSink{baseSink: baseSink{down: down}}
 }
 
+func (f *opDistinct[E]) buffering() bool {
+	return true
+}
+
 // endregion
 
 // region DistinctBy
@@ -200,6 +220,10 @@ func (s *distinctBySink[E]) Accept(v any) {
 	s.m[s.id(v.(E))] = v
 }
 
+func (s *distinctBySink[E]) bufferLen() int {
+	return len(s.m)
+}
+
 func (s *distinctBySink[E]) Close() {
 	s.down.Begin(uint64(len(s.m)), true)
 	for _, v := range s.m {
@@ -215,6 +239,10 @@ func (f *opDistinctBy[E]) WrapSink(down sink) sink {
 	return &distinctBySink[E]{baseSink: baseSink{down: down}, id: f.id}
 }
 
+func (f *opDistinctBy[E]) buffering() bool {
+	return true
+}
+
 // endregion
 
 // region SortBy
@@ -244,6 +272,10 @@ func (s *sortBySink[E]) Accept(v any) {
 	s.slc = append(s.slc, v.(E))
 }
 
+func (s *sortBySink[E]) bufferLen() int {
+	return len(s.slc)
+}
+
 func (s *sortBySink[E]) Close() {
 	s.down.Begin(uint64(len(s.slc)), true)
 	sort.Slice(s.slc, func(i, j int) bool {
@@ -262,4 +294,276 @@ func (f *opSortBy[E]) WrapSink(down sink) sink {
 	return &sortBySink[E]{baseSink: baseSink{down: down}, cmp: f.cmp}
 }
 
+func (f *opSortBy[E]) buffering() bool {
+	return true
+}
+
+// endregion
+
+// region Sample
+
+type opSample[E any] struct {
+	base[E]
+	k   uint64
+	rng *rand.Rand
+}
+
+func newOpSample[E any](meta *meta, upstream pipeline, k uint64, rng *rand.Rand) (ret *opSample[E]) {
+	ret = &opSample[E]{k: k, rng: rng}
+	ret.base = base[E]{Meta: meta.LimitSize(k).SetSinkIterable(false), Prev: upstream, Curr: ret}
+	return
+}
+
+type sampleSink[E any] struct {
+	baseSink
+	k   uint64
+	n   uint64
+	rng *rand.Rand
+	slc []E
+}
+
+func (s *sampleSink[E]) Begin(size uint64, _ bool) {
+	s.slc = make([]E, 0, Min(s.k, size))
+}
+
+func (s *sampleSink[E]) Accept(v any) {
+	s.n++
+	if uint64(len(s.slc)) < s.k {
+		s.slc = append(s.slc, v.(E))
+		return
+	}
+	if j := uint64(s.rng.Int63n(int64(Min(s.n, math.MaxInt64)))); j < s.k {
+		s.slc[j] = v.(E)
+	}
+}
+
+func (s *sampleSink[E]) bufferLen() int {
+	return len(s.slc)
+}
+
+func (s *sampleSink[E]) Close() {
+	s.down.Begin(uint64(len(s.slc)), true)
+	for _, v := range s.slc {
+		if s.down.Rejecting() {
+			break
+		}
+		s.down.Accept(v)
+	}
+	s.down.Close()
+}
+
+func (f *opSample[E]) WrapSink(down sink) sink {
+	return &sampleSink[E]{baseSink: baseSink{down: down}, k: f.k, rng: f.rng}
+}
+
+func (f *opSample[E]) buffering() bool {
+	return true
+}
+
+// endregion
+
+// region SampleWeighted
+
+type opSampleWeighted[E any] struct {
+	base[E]
+	k      uint64
+	weight func(v E) float64
+	rng    *rand.Rand
+}
+
+func newOpSampleWeighted[E any](meta *meta, upstream pipeline, k uint64, weight func(v E) float64,
+	rng *rand.Rand) (ret *opSampleWeighted[E]) {
+	ret = &opSampleWeighted[E]{k: k, weight: weight, rng: rng}
+	ret.base = base[E]{Meta: meta.LimitSize(k).SetSinkIterable(false), Prev: upstream, Curr: ret}
+	return
+}
+
+type weightedItem[E any] struct {
+	key float64
+	val E
+}
+
+// weightedHeap is a min-heap of weightedItem ordered by key.
+type weightedHeap[E any] []weightedItem[E]
+
+func (h weightedHeap[E]) Len() int           { return len(h) }
+func (h weightedHeap[E]) Less(i, j int) bool { return h[i].key < h[j].key }
+func (h weightedHeap[E]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *weightedHeap[E]) Push(x any)        { *h = append(*h, x.(weightedItem[E])) }
+func (h *weightedHeap[E]) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// sampleWeightedSink implements the A-Res algorithm of Efraimidis and Spirakis,
+// which keeps the k elements with the largest keys u^(1/w), computed as log(u)/w.
+type sampleWeightedSink[E any] struct {
+	baseSink
+	k      uint64
+	weight func(v E) float64
+	rng    *rand.Rand
+	h      weightedHeap[E]
+}
+
+func (s *sampleWeightedSink[E]) Begin(size uint64, _ bool) {
+	s.h = make(weightedHeap[E], 0, Min(s.k, size))
+}
+
+func (s *sampleWeightedSink[E]) Accept(v any) {
+	w := s.weight(v.(E))
+	if !(w > 0) {
+		return
+	}
+	key := math.Log(1-s.rng.Float64()) / w
+	if uint64(len(s.h)) < s.k {
+		heap.Push(&s.h, weightedItem[E]{key: key, val: v.(E)})
+	} else if key > s.h[0].key {
+		s.h[0] = weightedItem[E]{key: key, val: v.(E)}
+		heap.Fix(&s.h, 0)
+	}
+}
+
+func (s *sampleWeightedSink[E]) bufferLen() int {
+	return len(s.h)
+}
+
+func (s *sampleWeightedSink[E]) Close() {
+	s.down.Begin(uint64(len(s.h)), true)
+	for _, item := range s.h {
+		if s.down.Rejecting() {
+			break
+		}
+		s.down.Accept(item.val)
+	}
+	s.down.Close()
+}
+
+func (f *opSampleWeighted[E]) WrapSink(down sink) sink {
+	return &sampleWeightedSink[E]{baseSink: baseSink{down: down}, k: f.k, weight: f.weight, rng: f.rng}
+}
+
+func (f *opSampleWeighted[E]) buffering() bool {
+	return true
+}
+
+// endregion
+
+// region Shuffle
+
+type opShuffle[E any] struct {
+	base[E]
+	rng *rand.Rand
+}
+
+func newOpShuffle[E any](meta *meta, upstream pipeline, rng *rand.Rand) (ret *opShuffle[E]) {
+	ret = &opShuffle[E]{rng: rng}
+	ret.base = base[E]{Meta: meta.SetSinkIterable(false), Prev: upstream, Curr: ret}
+	return
+}
+
+type shuffleSink[E any] struct {
+	baseSink
+	rng *rand.Rand
+	slc []E
+}
+
+func (s *shuffleSink[E]) Begin(size uint64, _ bool) {
+	s.slc = make([]E, 0, size)
+}
+
+func (s *shuffleSink[E]) Accept(v any) {
+	s.slc = append(s.slc, v.(E))
+}
+
+func (s *shuffleSink[E]) bufferLen() int {
+	return len(s.slc)
+}
+
+func (s *shuffleSink[E]) Close() {
+	s.down.Begin(uint64(len(s.slc)), true)
+	s.rng.Shuffle(len(s.slc), func(i, j int) {
+		s.slc[i], s.slc[j] = s.slc[j], s.slc[i]
+	})
+	for _, v := range s.slc {
+		if s.down.Rejecting() {
+			break
+		}
+		s.down.Accept(v)
+	}
+	s.down.Close()
+}
+
+func (f *opShuffle[E]) WrapSink(down sink) sink {
+	return &shuffleSink[E]{baseSink: baseSink{down: down}, rng: f.rng}
+}
+
+func (f *opShuffle[E]) buffering() bool {
+	return true
+}
+
+// endregion
+
+// region Reverse
+
+type opReverse[E any] struct {
+	base[E]
+	reversible bool // whether the upstream is a source able to iterate backward without buffering.
+}
+
+func newOpReverse[E any](meta *meta, upstream pipeline) (ret *opReverse[E]) {
+	ret = &opReverse[E]{}
+	if h, ok := upstream.(*header[E]); ok {
+		_, ret.reversible = h.src.(iterator.ReversibleIterable[E])
+	}
+	if !ret.reversible {
+		meta.SetSinkIterable(false)
+	}
+	ret.base = base[E]{Meta: meta, Prev: upstream, Curr: ret}
+	return
+}
+
+type reverseSink[E any] struct {
+	baseSink
+	slc []E
+}
+
+func (s *reverseSink[E]) Begin(size uint64, known bool) {
+	if known {
+		s.slc = make([]E, 0, size)
+	}
+}
+
+func (s *reverseSink[E]) Accept(v any) {
+	s.slc = append(s.slc, v.(E))
+}
+
+func (s *reverseSink[E]) bufferLen() int {
+	return len(s.slc)
+}
+
+func (s *reverseSink[E]) Close() {
+	s.down.Begin(uint64(len(s.slc)), true)
+	for i := len(s.slc) - 1; i >= 0 && !s.down.Rejecting(); i-- {
+		s.down.Accept(s.slc[i])
+	}
+	s.down.Close()
+}
+
+func (f *opReverse[E]) WrapSink(down sink) sink {
+	return &reverseSink[E]{baseSink: baseSink{down: down}}
+}
+
+func (f *opReverse[E]) applySource(src pipeline) (iterator.Iterable[any], bool) {
+	if !f.reversible {
+		return nil, false
+	}
+	return wrapIterable[E](src.(*header[E]).src.(iterator.ReversibleIterable[E]).Reverse()), true
+}
+
+func (f *opReverse[E]) buffering() bool {
+	return !f.reversible
+}
+
 // endregion
@@ -0,0 +1,18 @@
+package stream
+
+import (
+	"github.com/not2dim/gostream/iterator"
+	"testing"
+)
+
+func TestSkipBeyondKnownSize(t *testing.T) {
+	src := func() Stream[int] {
+		return Iterable[int](iterator.SliceIterable[int]{0, 1, 2})
+	}
+	if cnt := src().Skip(5).Count(); cnt != 0 {
+		t.Fatalf("expected: %v, actual: %v\n", 0, cnt)
+	}
+	if slc := src().Skip(5).Collect(); len(slc) != 0 {
+		t.Fatalf("expected empty, actual: %v\n", slc)
+	}
+}
@@ -1,29 +1,137 @@
 package stream
 
-import "github.com/not2dim/gostream/iterator"
-
-func process(terminal pipeline) (header pipeline, wrapped sink) {
-	var pipelines []pipeline
-	var curr = terminal
-	for curr != nil {
+import (
+	"context"
+	"github.com/not2dim/gostream/iterator"
+	"sync"
+)
+
+// process wraps sinks of all pipelines from the terminal to the source, and returns the source Iterable,
+// whose Iterator runs all OnClose hooks of the pipelines when closed.
+func process(terminal pipeline) (src iterator.Iterable[any], wrapped sink) {
+	pipelines := walk(terminal)
+	consume(pipelines)
+	return wrapStages(pipelines, configOf(pipelines), nil, nil)
+}
+
+// walk returns the pipelines from the terminal to the source.
+func walk(terminal pipeline) (pipelines []pipeline) {
+	for curr := terminal; curr != nil; curr = curr.GetUpstream() {
 		pipelines = append(pipelines, curr)
-		curr = curr.GetUpstream()
 	}
-	wrapped = nil
-	for i := 0; i < len(pipelines)-1; i++ {
-		wrapped = pipelines[i].WrapSink(wrapped)
+	return
+}
+
+// configOf returns the meta holding the configs of metrics, panics and traces for a run of the pipelines,
+// which is the meta of the stage right before the terminal.
+func configOf(pipelines []pipeline) *meta {
+	if len(pipelines) < 2 {
+		return nil
+	}
+	return pipelines[1].GetMeta()
+}
+
+// wrapStages wraps sinks of the pipelines from the terminal to the source, where the sink of the terminal is
+// replaced by term if not nil. Stages upstream of the last Async stage are left to the Iterable it returns,
+// which runs them on another goroutine, so that each segment of the pipeline runs on its own. OnClose hooks of
+// the pipelines, followed by the given hooks of downstream segments, run once the source Iterator is closed, which
+// is on the goroutine of the most upstream segment, so that they run in order.
+func wrapStages(pipelines []pipeline, conf *meta, term sink, hooks []func()) (src iterator.Iterable[any],
+	wrapped sink) {
+	segment := pipelines
+	var async asyncStage
+	for k := 1; k < len(pipelines)-1; k++ {
+		if a, ok := pipelines[k].(asyncStage); ok {
+			segment, async = pipelines[:k+1], a
+			break
+		}
+	}
+	n, offset := len(segment), len(pipelines)-len(segment)
+	var segmentHooks []func()
+	for i := n - 1; i >= 0; i-- {
+		if h, ok := segment[i].(closeHooker); ok {
+			segmentHooks = append(segmentHooks, h.closeHook())
+		}
+	}
+	hooks = append(segmentHooks, hooks...)
+	if async != nil {
+		src = async.asyncSource(pipelines[n-1:], conf, hooks)
 	}
-	return pipelines[len(pipelines)-1], wrapped
+	// lets the stage right after the source apply itself to the source, e.g. Skip seeking instead of walking.
+	var applied bool
+	if s, ok := segment[Max(n-2, 0)].(sourceApplier); ok && src == nil && n > 1 {
+		src, applied = s.applySource(segment[n-1])
+	}
+	var run *metricsRun
+	var guard *panicGuard
+	var tr *traceRun
+	var stages segmentStages
+	if conf.Metrics() != nil || conf.Panic() != nil || conf.Trace() != nil {
+		stages = newSegmentStages(segment, offset)
+	}
+	if c := conf.Metrics(); c != nil {
+		run = newMetricsRun(c, stages)
+	}
+	if c := conf.Panic(); c != nil {
+		guard = &panicGuard{conf: c, stages: stages}
+	}
+	if c := conf.Trace(); c != nil {
+		tr = &traceRun{conf: c, stages: stages}
+	}
+	for i := 0; i < n-1; i++ {
+		if i == 0 && term != nil {
+			wrapped = term
+		} else if i == n-2 && applied {
+			// the stage is applied to the source already.
+		} else {
+			wrapped = segment[i].WrapSink(wrapped)
+		}
+		if run != nil {
+			wrapped = run.wrap(n-1-i, wrapped)
+		}
+		if guard != nil {
+			wrapped = guard.wrap(n-1-i, wrapped)
+		}
+		if tr != nil {
+			wrapped = tr.wrap(n-1-i, wrapped)
+		}
+	}
+	if async != nil {
+		return src, wrapped
+	}
+	if src == nil {
+		src = segment[n-1].GetSource()
+	}
+	if len(hooks) > 0 {
+		src = hookedIterable{inner: src, hooks: hooks}
+	}
+	return src, wrapped
+}
+
+// sourceApplier is implemented by stages able to apply themselves to the source right before them, whose sinks
+// are then left out of the run, e.g. Skip seeking the source Iterator instead of walking.
+type sourceApplier interface {
+	applySource(src pipeline) (iterator.Iterable[any], bool)
 }
 
 func terminate(terminal pipeline) {
-	header, wrapped := process(terminal)
-	src := header.GetSource()
+	src, wrapped := process(terminal)
+	run(src, wrapped)
+}
+
+// run pushes all elements of the source into the wrapped sink.
+func run(src iterator.Iterable[any], wrapped sink) {
 	size, known := src.Size()
 	iter := src.Iterator()
-	defer iter.Close()
+	defer func() {
+		iter.Close()
+		if w, ok := iter.(Waiter); ok {
+			// the run ends only after the upstream segment on another goroutine, e.g. of Async, and its hooks.
+			_ = w.Wait(context.Background())
+		}
+	}()
 	wrapped.Begin(size, known)
-	for iter.MoveNext() && !wrapped.Rejecting() {
+	for !wrapped.Rejecting() && iter.MoveNext() {
 		wrapped.Accept(iter.Current())
 	}
 	wrapped.Close()
@@ -129,9 +237,19 @@ func (o *opForCond[E]) Terminate() {
 
 // region Iterator
 
+// Waiter is implemented by Iterators of Streams running stages on another goroutine, e.g. with FlatMap or Async.
+// Their Close stops the goroutine without waiting for it, since it may be blocked in the source, e.g. receiving
+// from a channel never sending, and the source is closed and OnClose hooks run on the goroutine once it finishes.
+type Waiter interface {
+	// Wait waits for the goroutine to finish, which is usually after Close or once the Iterator is exhausted,
+	// and re-raises its panic if any. It returns the error of ctx if ctx is done first.
+	Wait(ctx context.Context) error
+}
+
 type opIterator[E any] struct {
 	base[E]
-	current E
+	current  E
+	accepted bool // whether current is accepted by the latest push of a source element.
 }
 
 func newOpIterator[E any](meta *meta, upstream pipeline) (ret *opIterator[E]) {
@@ -147,6 +265,7 @@ type iterSink[E any] struct {
 
 func (f iterSink[E]) Accept(v any) {
 	f.op.current = v.(E)
+	f.op.accepted = true
 }
 
 func (f *opIterator[E]) WrapSink(_ sink) sink {
@@ -163,18 +282,25 @@ type sinkIterator[E any] struct {
 }
 
 func (f *sinkIterator[E]) MoveNext() bool {
+	if f.closed {
+		return false
+	}
 	if !f.begun {
 		f.begun = true
 		f.wrapped.Begin(f.size, f.known)
 	}
-	if !f.iter.MoveNext() || f.wrapped.Rejecting() {
-		f.closed = true
-		f.iter.Close()
-		f.wrapped.Close()
-		return false
+	// pushes source elements until one of them flows into the iterSink, since stages like Filter may drop them.
+	for f.iter.MoveNext() && !f.wrapped.Rejecting() {
+		f.op.accepted = false
+		f.wrapped.Accept(f.iter.Current())
+		if f.op.accepted {
+			return true
+		}
 	}
-	f.wrapped.Accept(f.iter.Current())
-	return true
+	f.closed = true
+	f.iter.Close()
+	f.wrapped.Close()
+	return false
 }
 
 func (f *sinkIterator[E]) Current() E {
@@ -182,20 +308,33 @@ func (f *sinkIterator[E]) Current() E {
 }
 
 func (f *sinkIterator[E]) Close() {
-	if f.begun && !f.closed {
-		f.closed = true
-		f.iter.Close()
+	if f.closed {
+		return
+	}
+	f.closed = true
+	defer f.iter.Close()
+	if f.begun {
 		f.wrapped.Close()
 	}
 }
 
+// Wait waits for the upstream segment of the last Async stage, if any.
+func (f *sinkIterator[E]) Wait(ctx context.Context) error {
+	if w, ok := f.iter.(Waiter); ok {
+		return w.Wait(ctx)
+	}
+	return nil
+}
+
 func (f *opIterator[E]) Build() iterator.Iterator[E] {
-	if f.Prev.GetSource() != nil {
+	switch iteratorMode(f.Meta, f.Prev) {
+	case modeEmpty:
+		return iterator.EmptyIterator[E]{}
+	case modeSource:
+		consume([]pipeline{f, f.Prev})
 		return unwrapIterable[E](f.Prev.GetSource()).Iterator()
-	}
-	if f.Meta.SinkIterable() {
-		header, wrapped := process(f)
-		src := header.GetSource()
+	case modeSink:
+		src, wrapped := process(f)
 		size, known := src.Size()
 		return &sinkIterator[E]{
 			op:      f,
@@ -205,49 +344,127 @@ func (f *opIterator[E]) Build() iterator.Iterator[E] {
 			wrapped: wrapped,
 		}
 	}
-	ch := make(chan E, 32)
+	pipelines := walk(f)
+	consume(pipelines)
+	return startChanneled[E](pipelines, configOf(pipelines), defaultChannelSize, nil)
+}
+
+// defaultChannelSize is the size of the channel of Iterator falling back to a goroutine, which can be tuned
+// by calling Async right before Iterator instead.
+const defaultChannelSize = 32
+
+// startChanneled runs the pipelines on a new goroutine, and returns a channeledIterator receiving elements
+// flowing into the terminal pipelines[0] through a channel buffering up to n elements. hooks of downstream
+// segments run on the goroutine after those of the pipelines.
+func startChanneled[E any](pipelines []pipeline, conf *meta, n int, hooks []func()) *channeledIterator[E] {
+	ch := make(chan E, n)
 	stop := make(chan struct{})
-	go func() {
-		newOpForCond(f.GetUpstream(),
-			func(v E) bool {
-				select {
-				case ch <- v:
-					return false
-				case <-stop:
-					return true
-				}
-			}, nil, func() {
-				close(ch)
-			},
-		).Terminate()
-	}()
-	return &channeledIterator[E]{
+	var closeCh sync.Once
+	src, wrapped := wrapStages(pipelines, conf, &chanSink[E]{
+		ch:   ch,
+		stop: stop,
+		termSink: termSink{close: func() {
+			closeCh.Do(func() { close(ch) })
+		}},
+	}, hooks)
+	ret := &channeledIterator[E]{
 		stop: stop,
 		ch:   ch,
 	}
+	done := make(chan struct{})
+	ret.done = done
+	go func() {
+		defer close(done)
+		defer func() {
+			// re-raised by the consumer, since nothing recovers panics of this goroutine.
+			ret.panicked = recover()
+			closeCh.Do(func() { close(ch) })
+		}()
+		run(src, wrapped)
+	}()
+	return ret
+}
+
+// chanSink sends elements to ch until stop is closed. It checks stop before every send, since select picks
+// a ready send at random even if stop is closed, and before the next source element is pulled.
+type chanSink[E any] struct {
+	termSink
+	ch      chan<- E
+	stop    <-chan struct{}
+	stopped bool
+}
+
+func (c *chanSink[E]) Accept(v any) {
+	if c.Rejecting() {
+		return
+	}
+	select {
+	case c.ch <- v.(E):
+	case <-c.stop:
+		c.stopped = true
+	}
+}
+
+func (c *chanSink[E]) Rejecting() bool {
+	if !c.stopped {
+		select {
+		case <-c.stop:
+			c.stopped = true
+		default:
+		}
+	}
+	return c.stopped
 }
 
 type channeledIterator[E any] struct {
-	stop chan<- struct{}
-	ch   <-chan E
-	curr E
+	stop     chan<- struct{}
+	done     <-chan struct{} // closed when the goroutine finishes, after the source iterator is closed.
+	ch       <-chan E
+	curr     E
+	stopped  bool
+	panicked any // written before done is closed.
 }
 
 func (f *channeledIterator[E]) MoveNext() bool {
 	tmp, ok := <-f.ch
 	if !ok {
+		// the goroutine has nothing left but closing the source once the channel is closed.
+		<-f.done
+		f.rethrow()
 		return false
 	}
 	f.curr = tmp
 	return true
 }
 
+// rethrow re-raises the panic of the goroutine, if any.
+func (f *channeledIterator[E]) rethrow() {
+	if r := f.panicked; r != nil {
+		f.panicked = nil
+		panic(r)
+	}
+}
+
 func (f *channeledIterator[E]) Current() E {
 	return f.curr
 }
 
+// Close stops the goroutine without waiting for it, which may be blocked in the source.
 func (f *channeledIterator[E]) Close() {
-	close(f.stop)
+	if !f.stopped {
+		f.stopped = true
+		close(f.stop)
+	}
+}
+
+func (f *channeledIterator[E]) Wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		f.rethrow()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // endregion
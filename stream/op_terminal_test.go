@@ -0,0 +1,29 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSinkIteratorDropped(t *testing.T) {
+	iter := Of(0, 1, 2, 3, 4, 5).Filter(func(v int) bool { return v%2 == 1 }).Iterator()
+	defer iter.Close()
+	var actual []int
+	for iter.MoveNext() {
+		actual = append(actual, iter.Current())
+	}
+	if expected := []int{1, 3, 5}; !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected: %v, actual: %v\n", expected, actual)
+	}
+}
+
+func TestRunPullsOnlyNeeded(t *testing.T) {
+	var moved int
+	src := Iterable[int](countingIterable{slc: Range(0, 10).Collect(), moved: &moved})
+	if slc := src.Filter(func(int) bool { return true }).Limit(3).Collect(); len(slc) != 3 {
+		t.Fatalf("expected: %v, actual: %v\n", 3, len(slc))
+	}
+	if moved != 3 {
+		t.Fatalf("expected: %v, actual: %v\n", 3, moved)
+	}
+}
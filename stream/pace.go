@@ -0,0 +1,187 @@
+package stream
+
+import (
+	"math"
+	"time"
+)
+
+// region Throttle
+
+type opThrottle[E any] struct {
+	base[E]
+	n     uint64
+	per   time.Duration
+	clock Clock
+}
+
+func newOpThrottle[E any](meta *meta, upstream pipeline, n uint64, per time.Duration, clock Clock) (ret *opThrottle[E]) {
+	if n == 0 {
+		panic("zero n of Throttle")
+	}
+	ret = &opThrottle[E]{n: n, per: per, clock: clock}
+	ret.base = base[E]{Meta: meta, Prev: upstream, Curr: ret}
+	return
+}
+
+type throttleSink[E any] struct {
+	baseSink
+	n     uint64
+	per   time.Duration
+	clock Clock
+	times []time.Time // ring of times of the latest n elements.
+	next  int
+}
+
+func (s *throttleSink[E]) Accept(v any) {
+	now := s.clock.Now()
+	if uint64(len(s.times)) < s.n {
+		s.times = append(s.times, now)
+	} else {
+		if wait := s.times[s.next].Add(s.per).Sub(now); wait > 0 {
+			s.clock.Sleep(wait)
+			now = s.clock.Now()
+		}
+		s.times[s.next] = now
+		s.next = (s.next + 1) % len(s.times)
+	}
+	s.down.Accept(v)
+}
+
+func (f *opThrottle[E]) WrapSink(down sink) sink {
+	return &throttleSink[E]{baseSink: baseSink{down: down}, n: f.n, per: f.per, clock: f.clock}
+}
+
+// endregion
+
+// region Delay
+
+type opDelay[E any] struct {
+	base[E]
+	d     time.Duration
+	clock Clock
+}
+
+func newOpDelay[E any](meta *meta, upstream pipeline, d time.Duration, clock Clock) (ret *opDelay[E]) {
+	ret = &opDelay[E]{d: d, clock: clock}
+	ret.base = base[E]{Meta: meta, Prev: upstream, Curr: ret}
+	return
+}
+
+type delaySink[E any] struct {
+	baseSink
+	d     time.Duration
+	clock Clock
+}
+
+func (s delaySink[E]) Accept(v any) {
+	s.clock.Sleep(s.d)
+	s.down.Accept(v)
+}
+
+func (f *opDelay[E]) WrapSink(down sink) sink {
+	return delaySink[E]{baseSink: baseSink{down: down}, d: f.d, clock: f.clock}
+}
+
+// endregion
+
+// region RateLimit
+
+type opRateLimit[E any] struct {
+	base[E]
+	rate  float64
+	burst uint64
+	clock Clock
+}
+
+func newOpRateLimit[E any](meta *meta, upstream pipeline, rate float64, burst uint64, clock Clock) (ret *opRateLimit[E]) {
+	if !(rate > 0) || burst == 0 {
+		panic("non-positive rate or zero burst of RateLimit")
+	}
+	ret = &opRateLimit[E]{rate: rate, burst: burst, clock: clock}
+	ret.base = base[E]{Meta: meta, Prev: upstream, Curr: ret}
+	return
+}
+
+// rateLimitSink is a token bucket, which starts full.
+type rateLimitSink[E any] struct {
+	baseSink
+	rate   float64
+	burst  float64
+	clock  Clock
+	tokens float64
+	last   time.Time
+}
+
+func (s *rateLimitSink[E]) refill() {
+	now := s.clock.Now()
+	if elapsed := now.Sub(s.last); elapsed > 0 {
+		s.tokens = math.Min(s.burst, s.tokens+elapsed.Seconds()*s.rate)
+	}
+	s.last = now
+}
+
+func (s *rateLimitSink[E]) Begin(size uint64, known bool) {
+	s.tokens, s.last = s.burst, s.clock.Now()
+	s.down.Begin(size, known)
+}
+
+func (s *rateLimitSink[E]) Accept(v any) {
+	s.refill()
+	if s.tokens < 1 {
+		s.clock.Sleep(time.Duration(math.Ceil((1 - s.tokens) / s.rate * float64(time.Second))))
+		s.refill()
+	}
+	s.tokens = math.Max(s.tokens-1, 0)
+	s.down.Accept(v)
+}
+
+func (f *opRateLimit[E]) WrapSink(down sink) sink {
+	return &rateLimitSink[E]{baseSink: baseSink{down: down}, rate: f.rate, burst: float64(f.burst), clock: f.clock}
+}
+
+// endregion
+
+// region Debounce
+
+type opDebounce[E any] struct {
+	base[E]
+	d     time.Duration
+	clock Clock
+}
+
+func newOpDebounce[E any](meta *meta, upstream pipeline, d time.Duration, clock Clock) (ret *opDebounce[E]) {
+	ret = &opDebounce[E]{d: d, clock: clock}
+	// an element is only emitted on the arrival of the next one, or when closed.
+	ret.base = base[E]{Meta: meta.SetSinkIterable(false), Prev: upstream, Curr: ret}
+	return
+}
+
+type debounceSink[E any] struct {
+	baseSink
+	d       time.Duration
+	clock   Clock
+	held    E
+	holding bool
+	last    time.Time
+}
+
+func (s *debounceSink[E]) Accept(v any) {
+	now := s.clock.Now()
+	if s.holding && now.Sub(s.last) >= s.d {
+		s.down.Accept(s.held)
+	}
+	s.held, s.holding, s.last = v.(E), true, now
+}
+
+func (s *debounceSink[E]) Close() {
+	if s.holding && !s.down.Rejecting() {
+		s.down.Accept(s.held)
+	}
+	s.down.Close()
+}
+
+func (f *opDebounce[E]) WrapSink(down sink) sink {
+	return &debounceSink[E]{baseSink: baseSink{down: down}, d: f.d, clock: f.clock}
+}
+
+// endregion
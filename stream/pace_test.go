@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStreamThrottle(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewFakeClock(start)
+	var times []time.Duration
+	cnt := Range(0, 7).Throttle(3, time.Second, clock).Peek(func(int) {
+		times = append(times, clock.Now().Sub(start))
+	}).Count()
+	expected := []time.Duration{0, 0, 0, time.Second, time.Second, time.Second, 2 * time.Second}
+	if cnt != 7 || !reflect.DeepEqual(expected, times) {
+		t.Fatalf("expected: %v, actual: %v\n", expected, times)
+	}
+
+	clock = NewFakeClock(start)
+	Range(0, 4).Peek(func(int) { clock.Advance(400 * time.Millisecond) }).Throttle(2, time.Second, clock).Count()
+	if slept := clock.Slept(); slept != 200*time.Millisecond {
+		t.Fatalf("case: elapsed elsewhere, expected: %v, actual: %v\n", 200*time.Millisecond, slept)
+	}
+}
+
+func TestStreamDelay(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	Range(0, 5).Delay(time.Second, clock).Limit(3).Count()
+	if slept := clock.Slept(); slept != 3*time.Second {
+		t.Fatalf("expected: %v, actual: %v\n", 3*time.Second, slept)
+	}
+}
+
+func TestStreamRateLimit(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewFakeClock(start)
+	var times []time.Duration
+	Range(0, 6).RateLimit(2, 3, clock).Foreach(func(int) {
+		times = append(times, clock.Now().Sub(start))
+	})
+	ms := time.Millisecond
+	expected := []time.Duration{0, 0, 0, 500 * ms, 1000 * ms, 1500 * ms}
+	if !reflect.DeepEqual(expected, times) {
+		t.Fatalf("expected: %v, actual: %v\n", expected, times)
+	}
+}
+
+func TestStreamDebounce(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	gaps := []time.Duration{0, 100, 100, 600, 100, 700, 0}
+	actual := Range(0, 7).Peek(func(v int) {
+		clock.Advance(gaps[v] * time.Millisecond)
+	}).Debounce(500*time.Millisecond, clock).Collect()
+	if expected := []int{2, 4, 6}; !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected: %v, actual: %v\n", expected, actual)
+	}
+}
+
+func TestContextClock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	begin := time.Now()
+	slc, err := TryCollect(Range(0, 10).Delay(time.Hour, ContextClock(ctx, nil)))
+	if !errors.Is(err, context.Canceled) || len(slc) != 0 || time.Since(begin) > time.Minute {
+		t.Fatalf("expected: %v, actual: %v, %v\n", context.Canceled, slc, err)
+	}
+
+	clock := NewFakeClock(time.Unix(0, 0))
+	ctx, cancel = context.WithCancel(context.Background())
+	slc, err = TryCollect(Range(0, 10).Delay(time.Second, ContextClock(ctx, clock)).Peek(func(v int) {
+		if v == 2 {
+			cancel()
+		}
+	}))
+	if !errors.Is(err, context.Canceled) || clock.Slept() != 3*time.Second {
+		t.Fatalf("expected: %v, actual: %v, %v\n", context.Canceled, err, clock.Slept())
+	}
+}
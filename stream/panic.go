@@ -0,0 +1,141 @@
+package stream
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicPolicy decides how panics in user funcs of stages, e.g. a Filter predicate or a Map mapper, are handled.
+type PanicPolicy uint8
+
+const (
+	// PanicPropagate propagates panics as they are, which is the default.
+	PanicPropagate PanicPolicy = iota
+	// PanicAsError recovers panics into PanicError and fails the run, so that error-aware terminals
+	// like TryCollect return the PanicError, while other terminals panic with a RunError wrapping it.
+	PanicAsError
+	// PanicSkip recovers panics in accepting an element, skips the element, and reports the PanicError to the
+	// onSkip func given to WithPanicPolicy. Panics out of accepting elements are handled as PanicAsError.
+	PanicSkip
+)
+
+// PanicError is a panic recovered from a user func of a stage.
+type PanicError struct {
+	// Stage is the index of the panicking stage, where the first stage after the source is 1, and Kind is its operation.
+	Stage int
+	Kind  string
+	// Value is the recovered value.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in stage #%d %s: %v", e.Stage, e.Kind, e.Value)
+}
+
+// Unwrap returns Value if it's an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+type panicConfig struct {
+	policy PanicPolicy
+	onSkip func(err *PanicError)
+}
+
+// WithPanicPolicy returns a new Stream[E] identical to s, except that panics in all stages of its pipeline,
+// including stages both before and after WithPanicPolicy, are handled by the given PanicPolicy.
+// onSkip can be nil, and is only called under PanicSkip.
+func WithPanicPolicy[E any](s Stream[E], policy PanicPolicy, onSkip func(err *PanicError)) Stream[E] {
+	var conf *panicConfig
+	if policy != PanicPropagate {
+		conf = &panicConfig{policy: policy, onSkip: onSkip}
+	}
+	return configure(s, "panicPolicy", func(m *meta) *meta { return m.SetPanic(conf) })
+}
+
+// panicGuard recovers panics of the stages of a segment in a run.
+type panicGuard struct {
+	conf   *panicConfig
+	stages segmentStages
+}
+
+// wrap returns a guardedSink recovering panics of the wrapped sink of the stage.
+func (g *panicGuard) wrap(stage int, wrapped sink) sink {
+	ret := &guardedSink{inner: wrapped, conf: g.conf}
+	ret.stage, ret.kind = g.stages.index(stage)
+	inner := wrapped
+	for w, ok := inner.(wrapperSink); ok; w, ok = inner.(wrapperSink) {
+		inner = w.unwrap()
+	}
+	ret.lagging, _ = inner.(laggingSink)
+	return ret
+}
+
+type guardedSink struct {
+	inner   sink
+	lagging laggingSink // the sink of the stage, unwrapped from wrapper sinks.
+	conf    *panicConfig
+	stage   int
+	kind    string
+}
+
+func (s *guardedSink) recover(accepting bool) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if _, ok := r.(RunError); ok {
+		panic(r) // already recovered by a downstream stage, or failed by an error.
+	}
+	err := &PanicError{Stage: s.stage, Kind: s.kind, Value: r, Stack: debug.Stack()}
+	if accepting && s.conf.policy == PanicSkip {
+		if s.conf.onSkip != nil {
+			s.conf.onSkip(err)
+		}
+		return
+	}
+	fail(err)
+}
+
+func (s *guardedSink) Begin(size uint64, known bool) {
+	defer s.recover(false)
+	s.inner.Begin(size, known)
+}
+
+func (s *guardedSink) Accept(v any) {
+	defer s.recover(true)
+	s.inner.Accept(v)
+}
+
+func (s *guardedSink) unwrap() sink {
+	return s.inner
+}
+
+func (s *guardedSink) Rejecting() bool {
+	return s.inner.Rejecting()
+}
+
+// laggingSink is implemented by sinks emitting results of accepted elements later, e.g. when closed.
+type laggingSink interface {
+	// emitNext waits for and emits the next lagging result, and returns false if none is left.
+	emitNext() bool
+}
+
+func (s *guardedSink) Close() {
+	if s.lagging != nil {
+		// panics of lagging results are handled as in accepting them, so that PanicSkip skips them as well.
+		for s.guardEmitNext() {
+		}
+	}
+	defer s.recover(false)
+	s.inner.Close()
+}
+
+func (s *guardedSink) guardEmitNext() (more bool) {
+	more = true
+	defer s.recover(true)
+	return s.lagging.emitNext()
+}
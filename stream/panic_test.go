@@ -0,0 +1,103 @@
+package stream
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithPanicPolicy(t *testing.T) {
+	mapper := func(v int) int {
+		if v%3 == 0 {
+			panic("multiple of 3")
+		}
+		return v
+	}
+	var skipped []*PanicError
+	actual := WithPanicPolicy(Range(1, 10).Map(mapper), PanicSkip, func(err *PanicError) {
+		skipped = append(skipped, err)
+	}).Collect()
+	if expected := []int{1, 2, 4, 5, 7, 8}; !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: skip, expected: %v, actual: %v\n", expected, actual)
+	}
+	if len(skipped) != 3 || skipped[0].Stage != 1 || skipped[0].Kind != "map" || skipped[0].Value != "multiple of 3" {
+		t.Fatalf("case: skipped, actual: %v\n", skipped)
+	}
+
+	slc, err := TryCollect(WithPanicPolicy(Range(1, 10).Map(mapper), PanicAsError, nil).Filter(func(int) bool { return true }))
+	var pe *PanicError
+	if slc != nil || !errors.As(err, &pe) || pe.Stage != 1 || pe.Kind != "map" || len(pe.Stack) == 0 {
+		t.Fatalf("case: as error, actual: %v, %v\n", slc, err)
+	}
+
+	cause := errors.New("cause")
+	err = TryForeach(WithPanicPolicy(Range(1, 10), PanicAsError, nil), func(int) { panic(cause) })
+	if !errors.Is(err, cause) || !errors.As(err, &pe) || pe.Stage != 2 {
+		t.Fatalf("case: terminal, actual: %v\n", err)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != "multiple of 3" {
+				t.Fatalf("case: propagate, actual: %v\n", r)
+			}
+		}()
+		WithPanicPolicy(Range(1, 10).Map(mapper), PanicPropagate, nil).Collect()
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r != "not failure" {
+				t.Fatalf("case: try, actual: %v\n", r)
+			}
+		}()
+		_ = Try(func() { panic("not failure") })
+	}()
+}
+
+func TestChanneledIteratorPanic(t *testing.T) {
+	iter := Range(0, 100).FlatMap(func(v int) Stream[int] {
+		if v == 50 {
+			panic("flat mapper")
+		}
+		return Of(v)
+	}).Iterator()
+	defer func() {
+		if r := recover(); r != "flat mapper" {
+			t.Fatalf("case: re-raised, actual: %v\n", r)
+		}
+		iter.Close()
+	}()
+	for iter.MoveNext() {
+	}
+	t.Fatalf("case: re-raised, expected panic\n")
+}
+
+func TestChanneledIteratorPanicAsError(t *testing.T) {
+	stm := WithPanicPolicy(Range(0, 100), PanicAsError, nil).FlatMap(func(v int) Stream[int] {
+		if v == 50 {
+			panic("flat mapper")
+		}
+		return Of(v)
+	})
+	err := Try(func() {
+		iter := stm.Iterator()
+		defer iter.Close()
+		for iter.MoveNext() {
+		}
+	})
+	var pe *PanicError
+	if !errors.As(err, &pe) || pe.Kind != "flatMap" {
+		t.Fatalf("case: channeled as error, actual: %v\n", err)
+	}
+}
+
+func TestPanicGuardUnwrap(t *testing.T) {
+	lagging := &mapConcurrentSink[int, int]{}
+	stages := segmentStages{kinds: []string{"source", "mapConcurrent"}}
+	guard := &panicGuard{conf: &panicConfig{policy: PanicSkip}, stages: stages}
+	wrapped := &guardedSink{inner: &meteredSink{inner: &tracedSink{inner: lagging}}}
+	if g := guard.wrap(1, wrapped).(*guardedSink); g.lagging != lagging {
+		t.Fatalf("expected the lagging sink under wrapper sinks\n")
+	}
+}
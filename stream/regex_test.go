@@ -0,0 +1,50 @@
+package stream
+
+import (
+	"errors"
+	"github.com/not2dim/gostream/iterator"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+type failingRuneReader struct {
+	*strings.Reader
+	err error
+}
+
+func (f failingRuneReader) ReadRune() (rune, int, error) {
+	r, size, err := f.Reader.ReadRune()
+	if err != nil {
+		err = f.err
+	}
+	return r, size, err
+}
+
+func TestRegex(t *testing.T) {
+	log := "GET /a 200\nPOST /b 500\nGET /c 503\nGET /d 200\n"
+	re := regexp.MustCompile(`(?m)^(\w+) (\S+) (5\d\d)$`)
+	first := RegexMatches(re, log).First()
+	if !first.OK || first.Val.Groups[2] != "/b" || first.Val.Index[0] != 11 {
+		t.Fatalf("case: first, actual: %v\n", first)
+	}
+	paths := Map(RegexReaderMatches(re, strings.NewReader(log)), func(m iterator.Match) string {
+		return m.Groups[2]
+	}).Collect()
+	if expected := []string{"/b", "/c"}; !reflect.DeepEqual(expected, paths) {
+		t.Fatalf("case: reader, expected: %v, actual: %v\n", expected, paths)
+	}
+	lines := RegexSplit(regexp.MustCompile(`\n`), []byte(log)).Filter(func(l string) bool {
+		return strings.HasPrefix(l, "GET")
+	}).Limit(2).Collect()
+	if expected := []string{"GET /a 200", "GET /c 503"}; !reflect.DeepEqual(expected, lines) {
+		t.Fatalf("case: split, expected: %v, actual: %v\n", expected, lines)
+	}
+
+	broken := errors.New("broken")
+	rr := failingRuneReader{Reader: strings.NewReader(log), err: broken}
+	if _, err := TryCollect(RegexReaderSplit(regexp.MustCompile(`\n`), rr)); err != broken {
+		t.Fatalf("case: error, actual: %v\n", err)
+	}
+}
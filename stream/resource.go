@@ -0,0 +1,161 @@
+package stream
+
+import "github.com/not2dim/gostream/iterator"
+
+// Using returns a new Stream[E] owning a resource R. On every run, open is called to acquire the resource,
+// body builds the Stream[E] upon it, and close releases it exactly once when the run finishes, short-circuits,
+// panics, or the Iterator is closed. If open fails, the run fails by the error and close is not called.
+func Using[R any, E any](open func() (R, error), body func(r R) Stream[E], close func(r R)) Stream[E] {
+	return newHeader[E](
+		defaultMeta.Copy(),
+		usingIterable[R, E]{open: open, body: body, close: close},
+	)
+}
+
+// region usingIterable
+
+type usingIterable[R any, E any] struct {
+	open  func() (R, error)
+	body  func(r R) Stream[E]
+	close func(r R)
+}
+
+func (u usingIterable[R, E]) Iterator() iterator.Iterator[E] {
+	r, err := u.open()
+	if err != nil {
+		fail(err)
+	}
+	ret := &usingIterator[E]{release: func() { u.close(r) }}
+	defer func() {
+		if ret.inner == nil {
+			ret.Close()
+		}
+	}()
+	ret.inner = u.body(r).Iterator()
+	return ret
+}
+
+func (u usingIterable[R, E]) Size() (n uint64, known bool) {
+	return 0, false
+}
+
+type usingIterator[E any] struct {
+	inner   iterator.Iterator[E]
+	release func()
+	closed  bool
+}
+
+func (u *usingIterator[E]) MoveNext() bool {
+	if u.closed {
+		return false
+	}
+	if !u.inner.MoveNext() {
+		u.Close()
+		return false
+	}
+	return true
+}
+
+func (u *usingIterator[E]) Current() E {
+	return u.inner.Current()
+}
+
+func (u *usingIterator[E]) Close() {
+	if u.closed {
+		return
+	}
+	u.closed = true
+	defer u.release()
+	if u.inner != nil {
+		u.inner.Close()
+	}
+}
+
+// endregion
+
+// region OnClose
+
+// closeHooker is implemented by pipelines holding a hook to run when a run of the pipeline finishes.
+type closeHooker interface {
+	closeHook() func()
+}
+
+type opOnClose[E any] struct {
+	base[E]
+	hook func()
+}
+
+func newOpOnClose[E any](meta *meta, upstream pipeline, hook func()) (ret *opOnClose[E]) {
+	ret = &opOnClose[E]{hook: hook}
+	ret.base = base[E]{Meta: meta, Prev: upstream, Curr: ret}
+	return
+}
+
+func (f *opOnClose[E]) WrapSink(down sink) sink {
+	return down
+}
+
+func (f *opOnClose[E]) closeHook() func() {
+	return f.hook
+}
+
+// endregion
+
+// region hookedIterable
+
+// hookedIterable wraps a source Iterable, whose Iterator runs all hooks in order exactly once when closed.
+type hookedIterable struct {
+	inner iterator.Iterable[any]
+	hooks []func()
+}
+
+func (h hookedIterable) Iterator() iterator.Iterator[any] {
+	ret := &hookedIterator{hooks: h.hooks}
+	defer func() {
+		if ret.inner == nil {
+			ret.Close()
+		}
+	}()
+	ret.inner = h.inner.Iterator()
+	return ret
+}
+
+func (h hookedIterable) Size() (n uint64, known bool) {
+	return h.inner.Size()
+}
+
+type hookedIterator struct {
+	inner  iterator.Iterator[any]
+	hooks  []func()
+	closed bool
+}
+
+func (h *hookedIterator) MoveNext() bool {
+	return h.inner.MoveNext()
+}
+
+func (h *hookedIterator) Current() any {
+	return h.inner.Current()
+}
+
+func (h *hookedIterator) Close() {
+	if h.closed {
+		return
+	}
+	h.closed = true
+	defer runHooks(h.hooks)
+	if h.inner != nil {
+		h.inner.Close()
+	}
+}
+
+// runHooks runs all hooks in order, even if some of them panic.
+func runHooks(hooks []func()) {
+	if len(hooks) == 0 {
+		return
+	}
+	defer runHooks(hooks[1:])
+	hooks[0]()
+}
+
+// endregion
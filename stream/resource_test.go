@@ -0,0 +1,192 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"github.com/not2dim/gostream/iterator"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestStreamOnClose(t *testing.T) {
+	var closed int
+	hook := func() { closed++ }
+	expectClosed := func(name string, expected int) {
+		t.Helper()
+		if closed != expected {
+			t.Fatalf("case: %v, expected: %v, actual: %v\n", name, expected, closed)
+		}
+	}
+	stm := WithReusePolicy(Range(0, 100), Reusable).OnClose(hook)
+	stm.Foreach(func(int) {})
+	expectClosed("finished", 1)
+	stm.Limit(3).Collect()
+	expectClosed("short-circuited", 2)
+	func() {
+		defer func() { _ = recover() }()
+		stm.Foreach(func(int) { panic("panicked") })
+	}()
+	expectClosed("panicked", 3)
+	iter := stm.Map(func(v int) int { return v }).Iterator()
+	iter.MoveNext()
+	iter.Close()
+	iter.Close()
+	expectClosed("sink iterator closed", 4)
+	iter = stm.FlatMap(func(v int) Stream[int] { return Of(v) }).Iterator()
+	iter.MoveNext()
+	iter.Close()
+	if err := iter.(Waiter).Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected: %v\n", err)
+	}
+	expectClosed("channeled iterator closed", 5)
+	iter = stm.FlatMap(func(v int) Stream[int] { return Of(v) }).Iterator()
+	for iter.MoveNext() {
+	}
+	expectClosed("channeled iterator exhausted", 6)
+	Of[int]().OnClose(hook).Count()
+	expectClosed("empty", 7)
+
+	var order []int
+	Of(0).OnClose(func() { order = append(order, 0) }).Filter(func(int) bool { return true }).
+		OnClose(func() { order = append(order, 1) }).Count()
+	if len(order) != 2 || order[0] != 0 || order[1] != 1 {
+		t.Fatalf("unexpected order: %v\n", order)
+	}
+}
+
+func TestStreamOnCloseEmptied(t *testing.T) {
+	var pulled int
+	src := func() Stream[int] {
+		return Of(1, 2, 3).Peek(func(int) { pulled++ })
+	}
+	for name, run := range map[string]func(hook func()){
+		"skip":       func(hook func()) { src().OnClose(hook).Skip(10).Count() },
+		"limit":      func(hook func()) { src().OnClose(hook).Limit(0).Count() },
+		"sample":     func(hook func()) { src().OnClose(hook).Sample(0, nil).Collect() },
+		"limit map":  func(hook func()) { Map(src().OnClose(hook).Limit(0), strconv.Itoa).Collect() },
+		"limit iter": func(hook func()) { src().OnClose(hook).Limit(0).Iterator().MoveNext() },
+		"empty skip": func(hook func()) { Of[int]().OnClose(hook).Skip(1).First() },
+	} {
+		var closed int
+		run(func() { closed++ })
+		if closed != 1 || pulled != 0 {
+			t.Fatalf("case: %v, closed: %v, pulled: %v\n", name, closed, pulled)
+		}
+	}
+	if !Of(1, 2, 3).Skip(10).(*emptyHeader[int]).Meta.Empty() {
+		t.Fatalf("expected unhooked Streams to collapse\n")
+	}
+}
+
+// blockingIterable signals entered on every MoveNext of its Iterator, before receiving the next element from ch.
+type blockingIterable struct {
+	entered chan<- struct{}
+	ch      <-chan int
+}
+
+func (b blockingIterable) Iterator() iterator.Iterator[int] {
+	return &blockingIterator{blockingIterable: b}
+}
+
+func (b blockingIterable) Size() (n uint64, known bool) {
+	return 0, false
+}
+
+type blockingIterator struct {
+	blockingIterable
+	curr int
+}
+
+func (b *blockingIterator) MoveNext() (ok bool) {
+	b.entered <- struct{}{}
+	b.curr, ok = <-b.ch
+	return
+}
+
+func (b *blockingIterator) Current() int {
+	return b.curr
+}
+
+func (b *blockingIterator) Close() {}
+
+func TestChanneledIteratorBlockedSource(t *testing.T) {
+	entered, ch := make(chan struct{}), make(chan int)
+	closed := make(chan struct{})
+	iter := Iterable[int](blockingIterable{entered: entered, ch: ch}).OnClose(func() { close(closed) }).
+		FlatMap(func(v int) Stream[int] { return Of(v) }).Iterator()
+	<-entered
+	iter.Close() // returns, though the goroutine is blocked receiving from ch.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := iter.(Waiter).Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected: %v, actual: %v\n", context.DeadlineExceeded, err)
+	}
+	ch <- 0 // unblocks the goroutine, which then sees the stop without pulling again.
+	if err := iter.(Waiter).Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected: %v\n", err)
+	}
+	<-closed
+}
+
+func TestAsyncOnCloseOrder(t *testing.T) {
+	var order []int
+	cnt := Range(0, 100).OnClose(func() { order = append(order, 0) }).Async(4).
+		OnClose(func() { order = append(order, 1) }).Limit(3).Count()
+	if cnt != 3 || len(order) != 2 || order[0] != 0 || order[1] != 1 {
+		t.Fatalf("unexpected, count: %v, order: %v\n", cnt, order)
+	}
+}
+
+func TestUsing(t *testing.T) {
+	var opened, closed int
+	stm := WithReusePolicy(Using(
+		func() (int, error) {
+			opened++
+			return 10, nil
+		},
+		func(n int) Stream[int] { return Range(0, n) },
+		func(int) { closed++ },
+	), Reusable)
+	if stm.Count() != 10 || stm.First().Val != 0 || opened != 2 || closed != 2 {
+		t.Fatalf("unexpected, opened: %v, closed: %v\n", opened, closed)
+	}
+	iter := stm.Iterator()
+	for iter.MoveNext() {
+	}
+	if closed != 3 {
+		t.Fatalf("expected: %v, actual: %v\n", 3, closed)
+	}
+	iter.Close()
+	if closed != 3 {
+		t.Fatalf("expected: %v, actual: %v\n", 3, closed)
+	}
+
+	errOpen := errors.New("failed to open")
+	func() {
+		defer func() {
+			if err, _ := recover().(error); !errors.Is(err, errOpen) {
+				t.Fatalf("expected: %v, actual: %v\n", errOpen, err)
+			}
+		}()
+		Using(
+			func() (int, error) { return 0, errOpen },
+			func(n int) Stream[int] { return Range(0, n) },
+			func(int) { closed++ },
+		).Count()
+	}()
+	if closed != 3 {
+		t.Fatalf("expected: %v, actual: %v\n", 3, closed)
+	}
+	func() {
+		defer func() { _ = recover() }()
+		Using(
+			func() (int, error) { return 0, nil },
+			func(n int) Stream[int] { panic("failed to build") },
+			func(int) { closed++ },
+		).Count()
+	}()
+	if closed != 4 {
+		t.Fatalf("expected: %v, actual: %v\n", 4, closed)
+	}
+}
@@ -0,0 +1,43 @@
+package stream
+
+import "errors"
+
+// ErrStreamConsumed is the panic value of running a SingleUse Stream, or any Stream derived from the same stage,
+// more than once.
+var ErrStreamConsumed = errors.New("stream has already been operated upon or closed")
+
+// ReusePolicy decides whether a Stream can be run by more than one terminal operation.
+type ReusePolicy uint8
+
+const (
+	// ReuseDefault follows the build-time default, which is SingleUse when built with the tag gostream_singleuse,
+	// and Reusable otherwise.
+	ReuseDefault ReusePolicy = iota
+	// Reusable Streams re-evaluate their whole pipeline on every terminal operation, by calling Iterator of the
+	// source Iterable again. Streams branching from the same stage also re-evaluate the shared stages separately.
+	// Thus, Reusable Streams are only suitable for sources whose Iterable can be iterated multiple times.
+	Reusable
+	// SingleUse Streams panic with ErrStreamConsumed when any stage of the pipeline has already been run by
+	// another terminal operation, either on the same Stream or on another Stream branching from a shared stage.
+	SingleUse
+)
+
+// WithReusePolicy returns a new Stream[E] identical to s, except that it's run under the given ReusePolicy.
+func WithReusePolicy[E any](s Stream[E], policy ReusePolicy) Stream[E] {
+	return configure(s, "reusePolicy", func(m *meta) *meta { return m.SetReusePolicy(policy) })
+}
+
+// consume marks all pipelines except the terminal consumed, where pipelines are ordered from the terminal
+// to the source as in process, and panics with ErrStreamConsumed if any of them was consumed under SingleUse.
+func consume(pipelines []pipeline) {
+	if len(pipelines) < 2 {
+		return
+	}
+	var consumed bool
+	for _, p := range pipelines[1:] {
+		consumed = p.MarkConsumed() || consumed
+	}
+	if consumed && pipelines[1].GetMeta().ReusePolicy() == SingleUse {
+		panic(ErrStreamConsumed)
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !gostream_singleuse
+
+package stream
+
+const defaultReusePolicy = Reusable
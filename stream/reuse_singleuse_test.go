@@ -0,0 +1,27 @@
+//go:build gostream_singleuse
+
+package stream
+
+import "testing"
+
+// Run with: go test -tags gostream_singleuse ./...
+// The tests of stream_test.go reuse Streams on purpose, so they are built without the tag only.
+
+func TestDefaultSingleUse(t *testing.T) {
+	stm := Of(0, 1, 2, 3).Filter(func(v int) bool { return v > 0 })
+	if stm.Count() != 3 {
+		t.Fail()
+	}
+	expectConsumed(t, func() { stm.Count() })
+	expectConsumed(t, func() { stm.Map(func(v int) int { return v }).Collect() })
+
+	reusable := WithReusePolicy(Of(0, 1, 2, 3), Reusable)
+	if reusable.Count() != 4 || reusable.Count() != 4 {
+		t.Fail()
+	}
+	if stm := Of(0, 1, 2, 3); stm.Skip(1).Count() != 3 {
+		t.Fail()
+	} else {
+		expectConsumed(t, func() { stm.Limit(1).Collect() })
+	}
+}
@@ -0,0 +1,45 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+)
+
+func expectConsumed(t *testing.T, f func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		if err, _ := recover().(error); !errors.Is(err, ErrStreamConsumed) {
+			t.Fatalf("expected panic: %v, actual: %v\n", ErrStreamConsumed, err)
+		}
+	}()
+	f()
+}
+
+func TestReusable(t *testing.T) {
+	stm := WithReusePolicy(Of(0, 1, 2, 3), Reusable).Filter(func(v int) bool { return v > 0 })
+	if stm.Count() != 3 || stm.Count() != 3 || len(stm.Limit(1).Collect()) != 1 {
+		t.Fail()
+	}
+}
+
+func TestSingleUse(t *testing.T) {
+	stm := WithReusePolicy(Of(0, 1, 2, 3), SingleUse)
+	filtered := stm.Filter(func(v int) bool { return v > 0 })
+	if filtered.Count() != 3 {
+		t.Fail()
+	}
+	expectConsumed(t, func() { filtered.Count() })
+	expectConsumed(t, func() { stm.Map(func(v int) int { return v }).Collect() })
+	expectConsumed(t, func() { stm.Iterator() })
+
+	stm = WithReusePolicy(Of(0, 1, 2, 3), SingleUse)
+	iter := stm.Iterator()
+	iter.Close()
+	expectConsumed(t, func() { stm.First() })
+
+	stm = WithReusePolicy(Of(0, 1, 2, 3), SingleUse).FlatMap(func(v int) Stream[int] { return Of(v) })
+	iter = stm.Iterator()
+	iter.Close()
+	expectConsumed(t, func() { stm.Iterator() })
+}
@@ -0,0 +1,77 @@
+package stream
+
+import (
+	"github.com/not2dim/gostream/iterator"
+	"reflect"
+	"testing"
+)
+
+// reversibleCounting is a countingIterable, which iterates backward without buffering.
+type reversibleCounting struct {
+	countingIterable
+}
+
+func (r reversibleCounting) Reverse() iterator.Iterable[int] {
+	return reversedCounting(r)
+}
+
+type reversedCounting reversibleCounting
+
+func (r reversedCounting) Iterator() iterator.Iterator[int] {
+	iter := iterator.SliceIterable[int](r.slc).Reverse().Iterator()
+	return iterator.Inspect(iter, func(int) { *r.moved++ })
+}
+
+func (r reversedCounting) Size() (n uint64, known bool) {
+	return uint64(len(r.slc)), true
+}
+
+func TestReverse(t *testing.T) {
+	if actual := Of(0, 1, 2, 3).Reverse().Collect(); !reflect.DeepEqual([]int{3, 2, 1, 0}, actual) {
+		t.Fatalf("case: slice, actual: %v\n", actual)
+	}
+	if actual := Range(0, 5).Reverse().Skip(1).Collect(); !reflect.DeepEqual([]int{3, 2, 1, 0}, actual) {
+		t.Fatalf("case: range, actual: %v\n", actual)
+	}
+	bytes := Iterable(iterator.BytesIterable[uint16]([]byte{1, 0, 2, 0, 3, 0})).Reverse().Collect()
+	if !reflect.DeepEqual([]uint16{3, 2, 1}, bytes) {
+		t.Fatalf("case: bytes, actual: %v\n", bytes)
+	}
+	actual := Range(0, 10).Filter(func(v int) bool { return v%3 == 0 }).Reverse().Limit(3).Collect()
+	if !reflect.DeepEqual([]int{9, 6, 3}, actual) {
+		t.Fatalf("case: buffered, actual: %v\n", actual)
+	}
+	if actual := Range(0, 6).Reverse().Reverse().Collect(); !reflect.DeepEqual([]int{0, 1, 2, 3, 4, 5}, actual) {
+		t.Fatalf("case: twice, actual: %v\n", actual)
+	}
+	iter := Range(0, 4).Filter(func(int) bool { return true }).Reverse().Iterator()
+	defer iter.Close()
+	var iterated []int
+	for iter.MoveNext() {
+		iterated = append(iterated, iter.Current())
+	}
+	if !reflect.DeepEqual([]int{3, 2, 1, 0}, iterated) {
+		t.Fatalf("case: iterator, actual: %v\n", iterated)
+	}
+}
+
+func TestReverseLimit(t *testing.T) {
+	for _, reversible := range []bool{true, false} {
+		var moved int
+		src := countingIterable{slc: Range(0, 100).Collect(), seekable: true, moved: &moved}
+		stm := Iterable[int](src)
+		if reversible {
+			stm = Iterable[int](reversibleCounting{src})
+		}
+		actual := stm.Reverse().Limit(3).Collect()
+		if expected := []int{99, 98, 97}; !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("case: %v, expected: %v, actual: %v\n", reversible, expected, actual)
+		}
+		if expected := map[bool]int{true: 3, false: 100}[reversible]; moved != expected {
+			t.Fatalf("case: %v moved, expected: %v, actual: %v\n", reversible, expected, moved)
+		}
+		if buffering := Describe(stm.Reverse())[1].Buffering; buffering == reversible {
+			t.Fatalf("case: %v buffering, actual: %v\n", reversible, buffering)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package stream
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/not2dim/gostream/iterator"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// Rows returns a new Stream[T] of rows scanned by scan, e.g. ScanStruct[T](). The error of scan or rows.Err()
+// fails the run. rows is closed once the run ends, even if it's short-circuited, so the Stream runs only once.
+func Rows[T any](rows *sql.Rows, scan func(rows *sql.Rows) (T, error)) Stream[T] {
+	return Iterable[T](rowsIterable[T]{rows: rows, scan: scan})
+}
+
+// RowsIterator returns an iterator.ErrIterator of rows scanned by scan, whose Close calls rows.Close(), and whose
+// Err returns the error of scan or rows.Err() once it stops.
+func RowsIterator[T any](rows *sql.Rows, scan func(rows *sql.Rows) (T, error)) iterator.ErrIterator[T] {
+	return &rowsIterator[T]{rows: rows, scan: scan}
+}
+
+// ScanStruct returns a scan func of Rows, which scans each column into the field of struct T of the same name.
+// A field is named by its `db` tag, or by its name compared case-insensitively and regardless of underscores
+// otherwise, where fields tagged `db:"-"` are ignored and fields of exported embedded structs are promoted.
+// Scanning fails if any column has no field. ScanStruct panics if T is not a struct.
+// The scan func is safe for concurrent use, e.g. by Streams of different rows running at the same time.
+func ScanStruct[T any]() func(rows *sql.Rows) (T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("%v is not a struct to scan", typ))
+	}
+	fields := structFields(typ, nil, map[string][]int{})
+	var last atomic.Value // *columnFields of the last rows scanned.
+	return func(rows *sql.Rows) (ret T, err error) {
+		cf, _ := last.Load().(*columnFields)
+		if cf == nil || cf.rows != rows {
+			cols, err := rows.Columns()
+			if err != nil {
+				return ret, err
+			}
+			cf = &columnFields{rows: rows, indexes: make([][]int, len(cols))}
+			for i, col := range cols {
+				if cf.indexes[i] = fields[normalizeColumn(col)]; cf.indexes[i] == nil {
+					return ret, fmt.Errorf("stream: no field of %v for column %q", typ, col)
+				}
+			}
+			last.Store(cf)
+		}
+		v := reflect.ValueOf(&ret).Elem()
+		dest := make([]any, len(cf.indexes))
+		for i, index := range cf.indexes {
+			dest[i] = v.FieldByIndex(index).Addr().Interface()
+		}
+		err = rows.Scan(dest...)
+		return
+	}
+}
+
+// columnFields holds the index of the field of each column of rows, which is never modified once stored. Holding
+// rows keeps it from being reclaimed, so that another *sql.Rows at the same address is never mistaken for it.
+type columnFields struct {
+	rows    *sql.Rows
+	indexes [][]int
+}
+
+// structFields collects the indexes of the exported fields of typ by their normalized names, where the fields
+// of outer structs win over those of embedded ones.
+func structFields(typ reflect.Type, prefix []int, fields map[string][]int) map[string][]int {
+	var embedded []reflect.StructField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag, tagged := f.Tag.Lookup("db")
+		if tag == "-" || !f.IsExported() {
+			continue
+		} else if f.Anonymous && !tagged && f.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, f)
+			continue
+		}
+		name := f.Name
+		if tag != "" {
+			name = tag
+		}
+		if key := normalizeColumn(name); fields[key] == nil {
+			fields[key] = append(append([]int{}, prefix...), i)
+		}
+	}
+	for _, f := range embedded {
+		structFields(f.Type, append(append([]int{}, prefix...), f.Index...), fields)
+	}
+	return fields
+}
+
+// normalizeColumn lowers name and drops its underscores, so that e.g. column user_id matches field UserID.
+func normalizeColumn(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// region rowsIterable
+
+type rowsIterable[T any] struct {
+	rows *sql.Rows
+	scan func(rows *sql.Rows) (T, error)
+}
+
+func (r rowsIterable[T]) Iterator() iterator.Iterator[T] {
+	return RowsIterator(r.rows, r.scan)
+}
+
+func (r rowsIterable[T]) Size() (n uint64, known bool) {
+	return 0, false
+}
+
+type rowsIterator[T any] struct {
+	rows *sql.Rows
+	scan func(rows *sql.Rows) (T, error)
+	curr T
+	err  error
+	done bool
+}
+
+func (r *rowsIterator[T]) MoveNext() bool {
+	if r.done {
+		return false
+	}
+	if !r.rows.Next() {
+		r.done, r.err = true, r.rows.Err()
+		return false
+	}
+	v, err := r.scan(r.rows)
+	if err != nil {
+		r.done, r.err = true, err
+		return false
+	}
+	r.curr = v
+	return true
+}
+
+func (r *rowsIterator[T]) Current() T {
+	return r.curr
+}
+
+func (r *rowsIterator[T]) Close() {
+	r.done = true
+	if err := r.rows.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *rowsIterator[T]) Err() error {
+	return r.err
+}
+
+// endregion
@@ -0,0 +1,220 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// region fake driver
+
+// fakeTable is the result of every query of a fakeDriver, which fails with err after all values.
+type fakeTable struct {
+	columns []string
+	values  [][]driver.Value
+	err     error
+	closed  int // count of closed rows.
+}
+
+type fakeDriver struct {
+	table *fakeTable
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return fakeConn(d), nil
+}
+
+func (d fakeDriver) Connect(context.Context) (driver.Conn, error) {
+	return fakeConn(d), nil
+}
+
+func (d fakeDriver) Driver() driver.Driver {
+	return d
+}
+
+type fakeConn struct {
+	table *fakeTable
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) {
+	return fakeStmt(c), nil
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fake: no transactions")
+}
+
+type fakeStmt struct {
+	table *fakeTable
+}
+
+func (s fakeStmt) Close() error {
+	return nil
+}
+
+func (s fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("fake: read only")
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{table: s.table}, nil
+}
+
+type fakeRows struct {
+	table *fakeTable
+	next  int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.table.columns
+}
+
+func (r *fakeRows) Close() error {
+	r.table.closed++
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next == len(r.table.values) {
+		if r.table.err != nil {
+			return r.table.err
+		}
+		return io.EOF
+	}
+	copy(dest, r.table.values[r.next])
+	r.next++
+	return nil
+}
+
+func queryFake(t *testing.T, table *fakeTable) *sql.Rows {
+	db := sql.OpenDB(fakeDriver{table: table})
+	t.Cleanup(func() { _ = db.Close() })
+	rows, err := db.Query("SELECT * FROM users")
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n", err)
+	}
+	return rows
+}
+
+// endregion
+
+type user struct {
+	UserID int64
+	Name   string `db:"full_name"`
+	Email  sql.NullString
+	Secret string `db:"-"`
+}
+
+func newUserTable() *fakeTable {
+	return &fakeTable{
+		columns: []string{"user_id", "full_name", "email"},
+		values: [][]driver.Value{
+			{int64(1), "Ann", "ann@x.org"},
+			{int64(2), "Bob", nil},
+			{int64(3), "Cid", "cid@x.org"},
+		},
+	}
+}
+
+func TestRows(t *testing.T) {
+	table := newUserTable()
+	names := Map(Rows(queryFake(t, table), ScanStruct[user]()).Filter(func(u user) bool {
+		return u.Email.Valid
+	}), func(u user) string { return u.Name }).Collect()
+	if expected := []string{"Ann", "Cid"}; !reflect.DeepEqual(expected, names) || table.closed != 1 {
+		t.Fatalf("case: struct, expected: %v, actual: %v, closed: %v\n", expected, names, table.closed)
+	}
+
+	table = newUserTable()
+	first := Rows(queryFake(t, table), func(rows *sql.Rows) (id int64, err error) {
+		var name, email any
+		err = rows.Scan(&id, &name, &email)
+		return
+	}).First()
+	if !first.OK || first.Val != 1 || table.closed != 1 {
+		t.Fatalf("case: short-circuited, actual: %v, closed: %v\n", first, table.closed)
+	}
+
+	table = newUserTable()
+	table.err = errors.New("connection reset")
+	slc, err := TryCollect(Rows(queryFake(t, table), ScanStruct[user]()))
+	if err != table.err || slc != nil || table.closed != 1 {
+		t.Fatalf("case: rows error, actual: %v, %v, closed: %v\n", slc, err, table.closed)
+	}
+
+	table = newUserTable()
+	table.columns = append(table.columns[:2:2], "phone")
+	_, err = TryCollect(Rows(queryFake(t, table), ScanStruct[user]()))
+	if err == nil || !strings.Contains(err.Error(), `column "phone"`) {
+		t.Fatalf("case: unknown column, actual: %v\n", err)
+	}
+
+	table = newUserTable()
+	iter := RowsIterator(queryFake(t, table), ScanStruct[user]())
+	if !iter.MoveNext() || iter.Current().UserID != 1 {
+		t.Fatalf("case: iterator, actual: %v\n", iter.Current())
+	}
+	iter.Close()
+	if iter.MoveNext() || iter.Err() != nil || table.closed != 1 {
+		t.Fatalf("case: iterator closed, err: %v, closed: %v\n", iter.Err(), table.closed)
+	}
+}
+
+func TestScanStructConcurrent(t *testing.T) {
+	scan := ScanStruct[user]()
+	tables := []*fakeTable{newUserTable(), newUserTable()}
+	// the columns of the second table are in another order.
+	tables[1].columns = []string{"email", "user_id", "full_name"}
+	for i, row := range tables[1].values {
+		tables[1].values[i] = []driver.Value{row[2], row[0], row[1]}
+	}
+	rows := []*sql.Rows{queryFake(t, tables[0]), queryFake(t, tables[1])}
+	results := make([][]string, len(rows))
+	done := make(chan struct{})
+	for i := range rows {
+		go func(i int) {
+			defer func() { done <- struct{}{} }()
+			results[i] = Map(Rows(rows[i], scan), func(u user) string { return u.Name }).Collect()
+		}(i)
+	}
+	<-done
+	<-done
+	for i, names := range results {
+		if expected := []string{"Ann", "Bob", "Cid"}; !reflect.DeepEqual(expected, names) {
+			t.Fatalf("case: rows %v, expected: %v, actual: %v\n", i, expected, names)
+		}
+	}
+}
+
+func TestScanStructFields(t *testing.T) {
+	table := &fakeTable{columns: []string{"CREATED_AT"}, values: [][]driver.Value{{"today"}}}
+	users, err := TryCollect(Rows(queryFake(t, table), ScanStruct[struct {
+		Audit
+	}]()))
+	if err != nil || len(users) != 1 || users[0].CreatedAt != "today" {
+		t.Fatalf("case: embedded, actual: %v, %v\n", users, err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("case: not a struct, expected a panic\n")
+		}
+	}()
+	ScanStruct[int]()
+}
+
+type Audit struct {
+	CreatedAt string
+}
@@ -0,0 +1,73 @@
+package stream
+
+import (
+	"github.com/not2dim/gostream/iterator"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// countingIterable counts elements moved to by its Iterators, which are Seekers if seekable.
+type countingIterable struct {
+	slc      []int
+	seekable bool
+	moved    *int
+}
+
+func (c countingIterable) Iterator() iterator.Iterator[int] {
+	iter := iterator.SliceIterator(c.slc)
+	counting := iterator.Inspect(iter, func(int) { *c.moved++ })
+	if !c.seekable {
+		return counting
+	}
+	return struct {
+		iterator.Iterator[int]
+		iterator.Seeker
+	}{counting, iter.(iterator.Seeker)}
+}
+
+func (c countingIterable) Size() (n uint64, known bool) {
+	return uint64(len(c.slc)), true
+}
+
+func TestSeekSource(t *testing.T) {
+	for _, seekable := range []bool{true, false} {
+		var moved int
+		src := func() Stream[int] {
+			return Iterable[int](countingIterable{slc: Range(0, 100).Collect(), seekable: seekable, moved: &moved})
+		}
+		actual := src().Skip(95).Filter(func(int) bool { return true }).Collect()
+		if expected := []int{95, 96, 97, 98, 99}; !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("case: skip %v, expected: %v, actual: %v\n", seekable, expected, actual)
+		}
+		if expected := map[bool]int{true: 5, false: 100}[seekable]; moved != expected {
+			t.Fatalf("case: skip %v moved, expected: %v, actual: %v\n", seekable, expected, moved)
+		}
+		moved = 0
+		if last := src().Last(); !last.OK || last.Val != 99 {
+			t.Fatalf("case: last %v, actual: %v\n", seekable, last)
+		}
+		if expected := map[bool]int{true: 1, false: 100}[seekable]; moved != expected {
+			t.Fatalf("case: last %v moved, expected: %v, actual: %v\n", seekable, expected, moved)
+		}
+	}
+	if last := Of[int]().Last(); last.OK {
+		t.Fatalf("case: last of empty, actual: %v\n", last)
+	}
+	if cnt := Range(0, 10).Skip(3).Skip(3).Count(); cnt != 4 {
+		t.Fatalf("case: skip twice, expected: %v, actual: %v\n", 4, cnt)
+	}
+}
+
+func TestRangeSeek(t *testing.T) {
+	if cnt := len(Range[int8](-100, 100).Collect()); cnt != 200 {
+		t.Fatalf("case: int8, expected: %v, actual: %v\n", 200, cnt)
+	}
+	if _, ok := newRangeIterable[uint64](0, math.MaxUint64).Iterator().(iterator.Seeker); ok {
+		t.Fatalf("case: huge, expected not seekable\n")
+	}
+	actual := Iterable(iterator.Reversed(newRangeIterable[int8](-2, 3))).Collect()
+	if expected := []int8{2, 1, 0, -1, -2}; !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: reversed, expected: %v, actual: %v\n", expected, actual)
+	}
+}
@@ -0,0 +1,290 @@
+package stream
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"github.com/not2dim/gostream/iterator"
+	"hash/maphash"
+	"math"
+	"math/bits"
+	"reflect"
+)
+
+// HashFunc writes v into h, so that equal values write the same bytes, e.g. for sketches of values which are not
+// hashed well by default, like structs of pointers to compare by content.
+type HashFunc[E any] func(h *maphash.Hash, v E)
+
+// hashValue hashes a comparable value, so that values equal by builtin == have the same hash.
+func hashValue[E comparable](h *maphash.Hash, v E) {
+	switch x := any(v).(type) {
+	case string:
+		_, _ = h.WriteString(x)
+	case int:
+		writeUint64(h, uint64(x))
+	default:
+		writeValue(h, reflect.ValueOf(any(v)))
+	}
+}
+
+func writeUint64(h *maphash.Hash, x uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], x)
+	_, _ = h.Write(buf[:])
+}
+
+// writeValue writes rv of a comparable type into h, where pointers and channels are written by address as == compares
+// them, and interfaces by their dynamic values.
+func writeValue(h *maphash.Hash, rv reflect.Value) {
+	switch rv.Kind() {
+	case reflect.String:
+		_, _ = h.WriteString(rv.String())
+	case reflect.Bool:
+		if rv.Bool() {
+			_ = h.WriteByte(1)
+		} else {
+			_ = h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(h, uint64(rv.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(h, rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f == 0 {
+			f = 0 // merges -0 into +0.
+		}
+		writeUint64(h, math.Float64bits(f))
+	case reflect.Complex64, reflect.Complex128:
+		c := rv.Complex()
+		writeValue(h, reflect.ValueOf(real(c)))
+		writeValue(h, reflect.ValueOf(imag(c)))
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint64(h, uint64(rv.Pointer()))
+	case reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			writeValue(h, rv.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			writeValue(h, rv.Field(i))
+		}
+	case reflect.Interface:
+		if !rv.IsNil() {
+			writeValue(h, rv.Elem())
+		}
+	}
+}
+
+// sum64 returns the hash of v by hash with seed.
+func sum64[E any](seed maphash.Seed, hash HashFunc[E], v E) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	hash(&h, v)
+	return h.Sum64()
+}
+
+// region CountMinSketch
+
+// CountMinSketch estimates occurrences of elements in width*depth counters.
+// Estimate never underestimates, and overestimates by at most e/width*Total with probability 1-exp(-depth).
+type CountMinSketch[E any] struct {
+	width, depth int
+	table        []uint64
+	seed         maphash.Seed
+	hash         HashFunc[E]
+	total        uint64
+}
+
+// NewCountMinSketch returns a new CountMinSketch of width*depth counters for comparable elements.
+func NewCountMinSketch[E comparable](width, depth int) *CountMinSketch[E] {
+	return NewCountMinSketchFunc(width, depth, hashValue[E])
+}
+
+// NewCountMinSketchFunc returns a new CountMinSketch of width*depth counters hashing elements by hash.
+func NewCountMinSketchFunc[E any](width, depth int, hash HashFunc[E]) *CountMinSketch[E] {
+	if width <= 0 || depth <= 0 {
+		panic("count-min sketch requires width > 0 and depth > 0")
+	}
+	return &CountMinSketch[E]{
+		width: width,
+		depth: depth,
+		table: make([]uint64, width*depth),
+		seed:  maphash.MakeSeed(),
+		hash:  hash,
+	}
+}
+
+// cells calls f with the counter index of v in each row, using the double hashing of Kirsch and Mitzenmacher.
+func (c *CountMinSketch[E]) cells(v E, f func(idx int)) {
+	h := sum64(c.seed, c.hash, v)
+	h1, h2 := h&math.MaxUint32, h>>32
+	for i := 0; i < c.depth; i++ {
+		f(i*c.width + int((h1+uint64(i)*h2)%uint64(c.width)))
+	}
+}
+
+// Add adds n occurrences of v.
+func (c *CountMinSketch[E]) Add(v E, n uint64) {
+	c.total += n
+	c.cells(v, func(idx int) {
+		c.table[idx] += n
+	})
+}
+
+// Estimate returns the estimated occurrences of v.
+func (c *CountMinSketch[E]) Estimate(v E) uint64 {
+	var est uint64 = math.MaxUint64
+	c.cells(v, func(idx int) {
+		est = Min(est, c.table[idx])
+	})
+	return est
+}
+
+// Total returns the total occurrences added.
+func (c *CountMinSketch[E]) Total() uint64 {
+	return c.total
+}
+
+// ToCountMinSketch collects elements of the Stream[E] into a new CountMinSketch of width*depth counters.
+func ToCountMinSketch[E comparable](s Stream[E], width, depth int) *CountMinSketch[E] {
+	cms := NewCountMinSketch[E](width, depth)
+	s.Foreach(func(v E) { cms.Add(v, 1) })
+	return cms
+}
+
+type hitter[E comparable] struct {
+	val E
+	cnt uint64
+}
+
+// hitterHeap is a min-heap of hitter ordered by cnt, with idx tracking positions of values.
+type hitterHeap[E comparable] struct {
+	items []hitter[E]
+	idx   map[E]int
+}
+
+func (h *hitterHeap[E]) Len() int           { return len(h.items) }
+func (h *hitterHeap[E]) Less(i, j int) bool { return h.items[i].cnt < h.items[j].cnt }
+func (h *hitterHeap[E]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.idx[h.items[i].val] = i
+	h.idx[h.items[j].val] = j
+}
+func (h *hitterHeap[E]) Push(x any) {
+	h.idx[x.(hitter[E]).val] = len(h.items)
+	h.items = append(h.items, x.(hitter[E]))
+}
+func (h *hitterHeap[E]) Pop() any {
+	item := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	delete(h.idx, item.val)
+	return item
+}
+
+// HeavyHitters returns approximately the k most frequent elements of the Stream[E] with their estimated
+// occurrences in descending order, using a CountMinSketch of width*depth counters and O(k) candidates.
+func HeavyHitters[E comparable](s Stream[E], k, width, depth int) []iterator.Pair[E, uint64] {
+	if k <= 0 {
+		return nil
+	}
+	cms := NewCountMinSketch[E](width, depth)
+	h := &hitterHeap[E]{idx: make(map[E]int, k)}
+	s.Foreach(func(v E) {
+		cms.Add(v, 1)
+		est := cms.Estimate(v)
+		if i, ok := h.idx[v]; ok {
+			h.items[i].cnt = est
+			heap.Fix(h, i)
+		} else if h.Len() < k {
+			heap.Push(h, hitter[E]{val: v, cnt: est})
+		} else if est > h.items[0].cnt {
+			heap.Pop(h)
+			heap.Push(h, hitter[E]{val: v, cnt: est})
+		}
+	})
+	ret := make([]iterator.Pair[E, uint64], h.Len())
+	for i := len(ret) - 1; i >= 0; i-- {
+		item := heap.Pop(h).(hitter[E])
+		ret[i] = iterator.Pair[E, uint64]{First: item.val, Second: item.cnt}
+	}
+	return ret
+}
+
+// endregion
+
+// region HyperLogLog
+
+// HyperLogLog estimates the count of distinct elements in 2^precision bytes.
+// The standard error of Estimate is about 1.04/sqrt(2^precision).
+type HyperLogLog[E any] struct {
+	p    uint8
+	regs []uint8
+	seed maphash.Seed
+	hash HashFunc[E]
+}
+
+// NewHyperLogLog returns a new HyperLogLog of 2^precision registers for comparable elements, where precision is
+// clamped to [4, 18].
+func NewHyperLogLog[E comparable](precision uint8) *HyperLogLog[E] {
+	return NewHyperLogLogFunc(precision, hashValue[E])
+}
+
+// NewHyperLogLogFunc is like NewHyperLogLog, but hashes elements by hash.
+func NewHyperLogLogFunc[E any](precision uint8, hash HashFunc[E]) *HyperLogLog[E] {
+	precision = Min(Max(precision, 4), 18)
+	return &HyperLogLog[E]{
+		p:    precision,
+		regs: make([]uint8, 1<<precision),
+		seed: maphash.MakeSeed(),
+		hash: hash,
+	}
+}
+
+// Add adds v.
+func (h *HyperLogLog[E]) Add(v E) {
+	x := sum64(h.seed, h.hash, v)
+	idx := x >> (64 - h.p)
+	rank := uint8(bits.LeadingZeros64(x<<h.p|1<<(h.p-1))) + 1
+	if rank > h.regs[idx] {
+		h.regs[idx] = rank
+	}
+}
+
+// Estimate returns the estimated count of distinct elements added.
+func (h *HyperLogLog[E]) Estimate() uint64 {
+	m := float64(len(h.regs))
+	var sum float64
+	var zeros int
+	for _, r := range h.regs {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	var alpha float64
+	switch len(h.regs) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	default:
+		alpha = 0.7213 / (1 + 1.079/m)
+	}
+	est := alpha * m * m / sum
+	if est <= 2.5*m && zeros > 0 {
+		est = m * math.Log(m/float64(zeros))
+	}
+	return uint64(est + 0.5)
+}
+
+// CountDistinct returns the estimated count of distinct elements of the Stream[E]
+// by a HyperLogLog of 2^precision registers.
+func CountDistinct[E comparable](s Stream[E], precision uint8) uint64 {
+	hll := NewHyperLogLog[E](precision)
+	s.Foreach(hll.Add)
+	return hll.Estimate()
+}
+
+// endregion
@@ -0,0 +1,69 @@
+package stream
+
+import (
+	"hash/maphash"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	stm := Concat(
+		Map(Range(0, 10000), strconv.Itoa),
+		Map(Range(0, 1000), func(int) string { return "hot" }),
+	)
+	cms := ToCountMinSketch(stm, 2000, 5)
+	if cms.Total() != 11000 {
+		t.Fatalf("expected: %v, actual: %v\n", 11000, cms.Total())
+	}
+	if est := cms.Estimate("hot"); est < 1000 || est > 1030 {
+		t.Fatalf("expected about: %v, actual: %v\n", 1000, est)
+	}
+	if est := cms.Estimate("42"); est < 1 || est > 30 {
+		t.Fatalf("expected about: %v, actual: %v\n", 1, est)
+	}
+}
+
+func TestHeavyHitters(t *testing.T) {
+	stm := FlatMap(Range(0, 100), func(i int) Stream[int] {
+		if i < 3 {
+			return Map(Range(0, 1000*(i+1)), func(int) int { return i })
+		}
+		return Of(i)
+	})
+	hitters := HeavyHitters(stm, 3, 1000, 4)
+	if len(hitters) != 3 {
+		t.Fatalf("expected: %v, actual: %v\n", 3, len(hitters))
+	}
+	for i, hit := range hitters {
+		if hit.First != 2-i || hit.Second < uint64(1000*(3-i)) {
+			t.Fatalf("unexpected: %v\n", hitters)
+		}
+	}
+}
+
+func TestCountDistinct(t *testing.T) {
+	for _, n := range []int{0, 10, 1000, 100000} {
+		stm := Concat(Range(0, n), Range(0, n))
+		est := CountDistinct(stm, 14)
+		if math.Abs(float64(est)-float64(n)) > float64(n)*0.05 {
+			t.Fatalf("expected about: %v, actual: %v\n", n, est)
+		}
+	}
+	type point struct{ x, y int }
+	if est := CountDistinct(Of(point{0, 1}, point{1, 0}, point{0, 1}), 10); est != 2 {
+		t.Fatalf("expected: %v, actual: %v\n", 2, est)
+	}
+	p0, p1 := &point{0, 1}, &point{0, 1}
+	if est := CountDistinct(Of(p0, p1, p0), 10); est != 2 {
+		t.Fatalf("expected: %v, actual: %v\n", 2, est)
+	}
+
+	hll := NewHyperLogLogFunc(10, func(h *maphash.Hash, v []byte) { _, _ = h.Write(v) })
+	for _, v := range [][]byte{[]byte("a"), []byte("b"), []byte("a")} {
+		hll.Add(v)
+	}
+	if est := hll.Estimate(); est != 2 {
+		t.Fatalf("expected: %v, actual: %v\n", 2, est)
+	}
+}
@@ -0,0 +1,245 @@
+package stream
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Stats is a one-pass summary of a numeric Stream. Min, Max, Mean and Variance are meaningless when Count is 0.
+type Stats[N realNum] struct {
+	Count    uint64
+	Min, Max N
+	Mean     float64
+	// Variance is the population variance.
+	Variance float64
+}
+
+// StdDev returns the population standard deviation.
+func (s Stats[N]) StdDev() float64 {
+	return math.Sqrt(s.Variance)
+}
+
+// isFloat reports whether N is a floating-point type.
+func isFloat[N realNum]() bool {
+	return N(1)/N(2) != 0
+}
+
+// Sum returns the sum of all elements of the Stream[N].
+// Integer sums wrap around on overflow silently, see CheckedSum to detect it, and float sums are compensated to
+// reduce the rounding error.
+func Sum[N realNum](s Stream[N]) N {
+	if !isFloat[N]() {
+		return s.Reduce(0, func(b, a N) N { return b + a })
+	}
+	// Neumaier's variant of Kahan summation.
+	var sum, comp float64
+	s.Foreach(func(v N) {
+		f := float64(v)
+		t := sum + f
+		if math.Abs(sum) >= math.Abs(f) {
+			comp += (sum - t) + f
+		} else {
+			comp += (f - t) + sum
+		}
+		sum = t
+	})
+	return N(sum + comp)
+}
+
+// CheckedSum returns the sum of all elements of the integer Stream[N], and ok equals false if the sum overflows
+// at any point, in which case sum is meaningless.
+func CheckedSum[N integer | uinteger](s Stream[N]) (sum N, ok bool) {
+	ok = true
+	s.Foreach(func(v N) {
+		t := sum + v
+		if v > 0 && t < sum || v < 0 && t > sum {
+			ok = false
+		}
+		sum = t
+	})
+	return sum, ok
+}
+
+// Summary returns the count, min, max, mean and population variance of the Stream[N] in one pass,
+// using Welford's online algorithm.
+func Summary[N realNum](s Stream[N]) Stats[N] {
+	var st Stats[N]
+	var m2 float64
+	s.Foreach(func(v N) {
+		st.Count++
+		if st.Count == 1 {
+			st.Min, st.Max = v, v
+		} else {
+			st.Min, st.Max = Min(st.Min, v), Max(st.Max, v)
+		}
+		f := float64(v)
+		delta := f - st.Mean
+		st.Mean += delta / float64(st.Count)
+		m2 += delta * (f - st.Mean)
+	})
+	if st.Count > 0 {
+		st.Variance = m2 / float64(st.Count)
+	}
+	return st
+}
+
+// Average returns the arithmetic mean of all elements of the Stream[N].
+func Average[N realNum](s Stream[N]) Nullable[float64] {
+	st := Summary(s)
+	return Nullable[float64]{Val: st.Mean, OK: st.Count > 0}
+}
+
+// Variance returns the population variance of all elements of the Stream[N].
+func Variance[N realNum](s Stream[N]) Nullable[float64] {
+	st := Summary(s)
+	return Nullable[float64]{Val: st.Variance, OK: st.Count > 0}
+}
+
+// StdDev returns the population standard deviation of all elements of the Stream[N].
+func StdDev[N realNum](s Stream[N]) Nullable[float64] {
+	st := Summary(s)
+	return Nullable[float64]{Val: st.StdDev(), OK: st.Count > 0}
+}
+
+// Median returns the median of all elements of the Stream[N]. All elements are buffered.
+func Median[N realNum](s Stream[N]) Nullable[float64] {
+	return Percentile(s, 50)
+}
+
+// Percentile returns the p-th percentile (0 <= p <= 100) of all elements of the Stream[N],
+// linearly interpolated between the closest ranks, which is empty if the Stream is empty or p is NaN.
+// All elements are buffered.
+// For huge Streams, use Sketch for an approximation in bounded memory.
+func Percentile[N realNum](s Stream[N], p float64) Nullable[float64] {
+	if math.IsNaN(p) {
+		return Nullable[float64]{}
+	}
+	slc := ToSlice(Map(s, func(v N) float64 { return float64(v) }))
+	if len(slc) == 0 {
+		return Nullable[float64]{}
+	}
+	sort.Float64s(slc)
+	p = math.Min(math.Max(p, 0), 100)
+	rank := p / 100 * float64(len(slc)-1)
+	lo, hi := int(math.Floor(rank)), int(math.Ceil(rank))
+	return Nullable[float64]{Val: slc[lo] + (slc[hi]-slc[lo])*(rank-float64(lo)), OK: true}
+}
+
+// Sketch feeds all elements of the Stream[N] into a new QuantileSketch of accuracy parameter k.
+func Sketch[N realNum](s Stream[N], k int, rng *rand.Rand) *QuantileSketch {
+	sk := NewQuantileSketch(k, rng)
+	s.Foreach(func(v N) { sk.Add(float64(v)) })
+	return sk
+}
+
+// region QuantileSketch
+
+// QuantileSketch is a KLL sketch estimating quantiles of a float64 sequence in O(k) memory.
+// The rank error of Quantile is roughly O(1/k) of Count with high probability.
+type QuantileSketch struct {
+	k          int
+	rng        *rand.Rand
+	compactors [][]float64 // compactors[h] holds items of weight 2^h.
+	size       int
+	maxSize    int
+	n          uint64
+}
+
+// NewQuantileSketch returns a new QuantileSketch of accuracy parameter k, where k <= 0 means 200.
+// A nil rng is replaced by a randomly seeded one.
+func NewQuantileSketch(k int, rng *rand.Rand) *QuantileSketch {
+	if k <= 0 {
+		k = 200
+	}
+	q := &QuantileSketch{k: k, rng: newRand(rng)}
+	q.grow()
+	return q
+}
+
+func (q *QuantileSketch) capacity(h int) int {
+	depth := len(q.compactors) - h - 1
+	return int(math.Ceil(math.Pow(2.0/3.0, float64(depth))*float64(q.k))) + 1
+}
+
+func (q *QuantileSketch) grow() {
+	q.compactors = append(q.compactors, nil)
+	q.maxSize = 0
+	for h := range q.compactors {
+		q.maxSize += q.capacity(h)
+	}
+}
+
+// Add adds v into the sketch.
+func (q *QuantileSketch) Add(v float64) {
+	q.compactors[0] = append(q.compactors[0], v)
+	q.size++
+	q.n++
+	if q.size >= q.maxSize {
+		q.compress()
+	}
+}
+
+func (q *QuantileSketch) compress() {
+	for h := 0; h < len(q.compactors); h++ {
+		if len(q.compactors[h]) < q.capacity(h) {
+			continue
+		}
+		if h+1 >= len(q.compactors) {
+			q.grow()
+		}
+		items := q.compactors[h]
+		sort.Float64s(items)
+		var last []float64
+		if len(items)%2 == 1 {
+			last = append(last, items[len(items)-1])
+			items = items[:len(items)-1]
+		}
+		for i := q.rng.Intn(2); i < len(items); i += 2 {
+			q.compactors[h+1] = append(q.compactors[h+1], items[i])
+		}
+		q.compactors[h] = append(items[:0], last...)
+		q.size = 0
+		for _, c := range q.compactors {
+			q.size += len(c)
+		}
+		return
+	}
+}
+
+// Count returns the count of added values.
+func (q *QuantileSketch) Count() uint64 {
+	return q.n
+}
+
+// Quantile returns the estimated p-th quantile (0 <= p <= 1) of added values, or NaN if nothing is added.
+func (q *QuantileSketch) Quantile(p float64) float64 {
+	type weighted struct {
+		v float64
+		w uint64
+	}
+	var items []weighted
+	var total uint64
+	for h, c := range q.compactors {
+		for _, v := range c {
+			items = append(items, weighted{v, 1 << h})
+			total += 1 << h
+		}
+	}
+	if len(items) == 0 {
+		return math.NaN()
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].v < items[j].v })
+	p = math.Min(math.Max(p, 0), 1)
+	target := p * float64(total)
+	var cum uint64
+	for _, item := range items {
+		cum += item.w
+		if float64(cum) >= target {
+			return item.v
+		}
+	}
+	return items[len(items)-1].v
+}
+
+// endregion
@@ -0,0 +1,89 @@
+package stream
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	if Sum(Range(0, 100)) != 4950 {
+		t.Fail()
+	}
+	var slc []float64
+	for i := 0; i < 1000; i++ {
+		slc = append(slc, 1e16, 1, -1e16)
+	}
+	if sum := Sum(Slice(slc)); sum != 1000 {
+		t.Fatalf("expected: %v, actual: %v\n", 1000, sum)
+	}
+	if Sum(Of[int]()) != 0 {
+		t.Fail()
+	}
+}
+
+func TestCheckedSum(t *testing.T) {
+	if sum, ok := CheckedSum(Of[int8](100, 27, -128, 127)); !ok || sum != 126 {
+		t.Fatalf("unexpected: %v, %v\n", sum, ok)
+	}
+	for _, stm := range []Stream[int8]{Of[int8](100, 28), Of[int8](-100, -29), Of[int8](127, 1, -1)} {
+		if sum, ok := CheckedSum(stm); ok {
+			t.Fatalf("expected overflow, actual: %v\n", sum)
+		}
+	}
+	if _, ok := CheckedSum(Of[uint8](200, 56)); ok {
+		t.Fail()
+	}
+	if sum, ok := CheckedSum(Of[uint8](200, 55)); !ok || sum != 255 {
+		t.Fatalf("unexpected: %v, %v\n", sum, ok)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	st := Summary(Of[int8](2, 4, 4, 4, 5, 5, 7, 9))
+	if st.Count != 8 || st.Min != 2 || st.Max != 9 || st.Mean != 5 || st.Variance != 4 || st.StdDev() != 2 {
+		t.Fatalf("unexpected: %+v\n", st)
+	}
+	if avg := Average(Of[int8](100, 100, 100)); !avg.OK || avg.Val != 100 {
+		t.Fatalf("unexpected: %+v\n", avg)
+	}
+	if Average(Of[int]()).OK || Variance(Of[int]()).OK || StdDev(Of[int]()).OK {
+		t.Fail()
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	if m := Median(Of(3, 1, 2)); !m.OK || m.Val != 2 {
+		t.Fatalf("unexpected: %+v\n", m)
+	}
+	if m := Median(Of(4, 1, 3, 2)); !m.OK || m.Val != 2.5 {
+		t.Fatalf("unexpected: %+v\n", m)
+	}
+	for p, expected := range map[float64]float64{0: 1, 25: 25.75, 100: 100, 200: 100} {
+		if v := Percentile(Range(1, 101), p); !v.OK || v.Val != expected {
+			t.Fatalf("p: %v, expected: %v, actual: %+v\n", p, expected, v)
+		}
+	}
+	if Median(Of[float32]()).OK || Percentile(Of(1, 2), math.NaN()).OK {
+		t.Fail()
+	}
+}
+
+func TestQuantileSketch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	sk := Sketch(Range(0, 100000).Shuffle(rng), 200, rng)
+	if sk.Count() != 100000 {
+		t.Fatalf("expected: %v, actual: %v\n", 100000, sk.Count())
+	}
+	if sk.size > 1000 {
+		t.Fatalf("expected bounded memory, actual: %v items\n", sk.size)
+	}
+	for _, p := range []float64{0.01, 0.25, 0.5, 0.75, 0.99} {
+		if v := sk.Quantile(p); math.Abs(v-p*100000) > 2000 {
+			t.Fatalf("p: %v, expected about: %v, actual: %v\n", p, p*100000, v)
+		}
+	}
+	if !math.IsNaN(NewQuantileSketch(0, nil).Quantile(0.5)) {
+		t.Fail()
+	}
+}
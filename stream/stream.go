@@ -3,8 +3,14 @@ package stream
 import (
 	"bytes"
 	"github.com/not2dim/gostream/iterator"
+	"io"
+	"math/rand"
+	"regexp"
+	"time"
 )
 
+// Stream is a lazy pipeline of elements run by its terminal operations, e.g. Collect, which panic with a RunError
+// if the run fails by an error, see Try.
 type Stream[E any] interface {
 	// Skip skips the first n elements.
 	Skip(n uint64) Stream[E]
@@ -31,10 +37,39 @@ type Stream[E any] interface {
 	Last() Nullable[E]
 	// SortBy sorts elements in the Stream according to the provided func cmp.
 	SortBy(cmp func(u, v E) int) Stream[E]
+	// Reverse reverses the order of elements. Sources with random access, e.g. Slice and Range, are iterated
+	// backward without buffering, so that e.g. Limit(n) after Reverse only touches the last n elements.
+	// Otherwise, all elements are buffered until the upstream is exhausted.
+	Reverse() Stream[E]
+	// Sample selects k elements uniformly at random by reservoir sampling, buffering at most k elements.
+	// A nil rng is replaced by a randomly seeded one; pass rand.New(rand.NewSource(seed)) for reproducible results.
+	Sample(k uint64, rng *rand.Rand) Stream[E]
+	// SampleWeighted selects k elements at random by weighted reservoir sampling, where each element is chosen
+	// with probability proportional to weight(v). Elements with non-positive weights are never selected.
+	SampleWeighted(k uint64, weight func(v E) float64, rng *rand.Rand) Stream[E]
+	// SampleFraction selects each element independently with probability p.
+	SampleFraction(p float64, rng *rand.Rand) Stream[E]
+	// Shuffle buffers all elements and emits them in a random order.
+	Shuffle(rng *rand.Rand) Stream[E]
+	// Throttle paces elements, so that at most n elements flow downstream in any period of the given duration.
+	// A nil clock is replaced by the real one; pass ContextClock to make waits cancellable.
+	Throttle(n uint64, per time.Duration, clock Clock) Stream[E]
+	// Delay waits for d before each element flows downstream.
+	Delay(d time.Duration, clock Clock) Stream[E]
+	// RateLimit paces elements by a token bucket, which holds up to burst tokens, starts full,
+	// and refills at rate tokens per second. Each element takes one token.
+	RateLimit(rate float64, burst uint64, clock Clock) Stream[E]
+	// Debounce drops elements followed by another element within d, and emits the last element when closed.
+	Debounce(d time.Duration, clock Clock) Stream[E]
 	// Map applies the given func mapper to every element.
 	Map(mapper func(v E) E) Stream[E]
 	// FlatMap applies the given func mapper to every element.
 	FlatMap(mapper func(v E) Stream[E]) Stream[E]
+	// Async decouples the upstream stages from the downstream ones, by running the upstream on its own goroutine
+	// and passing elements through a channel buffering up to n elements, so that e.g. an I/O-bound source overlaps
+	// with CPU-bound mapping. Calling Async right before Iterator tunes the channel of the Iterator.
+	// Tracers, metrics registries and onSkip funcs of the pipeline may be called concurrently by both sides.
+	Async(n int) Stream[E]
 	// Count returns the count of elements in the Stream.
 	Count() uint64
 	// Collect collects all elements into a slice.
@@ -46,8 +81,12 @@ type Stream[E any] interface {
 	Foreach(act func(v E))
 	// ForCond applies the provided func cond to each iterated element until cond(v) returns true.
 	ForCond(cond func(v E) bool)
-	// Iterator returns an iterator of the Stream.
+	// Iterator returns an iterator of the Stream, which is a Waiter if it runs stages on another goroutine.
 	Iterator() iterator.Iterator[E]
+	// OnClose registers the func hook to run exactly once when a run of the Stream finishes,
+	// either normally, by short-circuiting, by panicking, or by closing its Iterator.
+	// Hooks of a pipeline run in the order they are registered.
+	OnClose(hook func()) Stream[E]
 }
 
 // Nullable denotes a non-existing Val when OK = false.
@@ -104,6 +143,35 @@ func Iterable[E any](iterable iterator.Iterable[E]) Stream[E] {
 	)
 }
 
+// FromChan returns a new Stream[E], whose elements are received from ch until it's closed.
+// Since elements are received only once, every run of the Stream continues where the last one stopped.
+func FromChan[E any](ch <-chan E) Stream[E] {
+	return Iterable[E](chanIterable[E]{ch: ch})
+}
+
+// RegexMatches returns a new Stream[iterator.Match] of successive matches of re in input, which are searched for
+// one at a time, so that e.g. First or Limit stops searching the rest. See iterator.RegexMatches.
+func RegexMatches[S string | []byte](re *regexp.Regexp, input S) Stream[iterator.Match] {
+	return Iterable(iterator.RegexMatches(re, input))
+}
+
+// RegexSplit returns a new Stream[string] of substrings of input between matches of re, like regexp.Regexp.Split
+// with n < 0. See iterator.RegexSplit.
+func RegexSplit[S string | []byte](re *regexp.Regexp, input S) Stream[string] {
+	return Iterable(iterator.RegexSplit(re, input))
+}
+
+// RegexReaderMatches is like RegexMatches but reads rr, whose error other than io.EOF fails the run.
+// Every run of the Stream continues where the last one stopped.
+func RegexReaderMatches(re *regexp.Regexp, rr io.RuneReader) Stream[iterator.Match] {
+	return Iterable(iterator.RegexReaderMatches(re, rr))
+}
+
+// RegexReaderSplit is like RegexSplit but reads rr like RegexReaderMatches.
+func RegexReaderSplit(re *regexp.Regexp, rr io.RuneReader) Stream[string] {
+	return Iterable(iterator.RegexReaderSplit(re, rr))
+}
+
 // Range returns a new Stream[E], whose elements are all integer or unsigned integer within [from, to).
 func Range[E integer | uinteger](from, to E) Stream[E] {
 	return Iterable[E](newRangeIterable(from, to))
@@ -213,13 +281,17 @@ func ToMap[E any, K comparable](s Stream[E], identity func(v E) K) map[K][]E {
 	)
 }
 
-// Joining concatenates every string-like element in Stream[E] into a string.
+// Joining concatenates every string-like element in Stream[E] into a string. Its buffer takes bufSize bytes at
+// least, or more if the size of the Stream is known, e.g. runes of iterator.RuneOptions with ExactSize.
 func Joining[E string | rune | byte](s Stream[E], delimiter string, bufSize uint64) string {
 	var idx int
 	return Collect(s,
 		func(size uint64, known bool) *bytes.Buffer {
-			if known && size > bufSize {
-				bufSize = size
+			// each element takes a byte at least, along with a delimiter between every two of them.
+			if delims, ok := mulSize(size-Min(size, 1), uint64(len(delimiter))); known && ok {
+				if n := addSize(size, delims); n > bufSize {
+					bufSize = n
+				}
 			}
 			return bytes.NewBuffer(make([]byte, 0, bufSize))
 		},
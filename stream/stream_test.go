@@ -1,6 +1,9 @@
+//go:build !gostream_singleuse
+
 package stream
 
 import (
+	"math/rand"
 	"sort"
 	"strconv"
 	"testing"
@@ -356,3 +359,66 @@ func TestToMap(t *testing.T) {
 		t.Logf("key: %v, val: %v\n", k, slc)
 	}
 }
+
+func TestStreamSample(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	if Range(0, 100).Sample(10, rng).Distinct().Count() != 10 {
+		t.Fail()
+	}
+	if Range(0, 5).Sample(10, rng).Count() != 5 {
+		t.Fail()
+	}
+	var hits [10]int
+	for i := 0; i < 10000; i++ {
+		Range(0, 10).Sample(3, rng).Foreach(func(v int) { hits[v]++ })
+	}
+	for v, hit := range hits {
+		if hit < 2700 || hit > 3300 {
+			t.Fatalf("element: %v, expected about 3000 hits, actual: %v\n", v, hit)
+		}
+	}
+	s0 := Range(0, 1000).Sample(5, rand.New(rand.NewSource(42))).Collect()
+	s1 := Range(0, 1000).Sample(5, rand.New(rand.NewSource(42))).Collect()
+	for i := range s0 {
+		if s0[i] != s1[i] {
+			t.Fatalf("expected: %v, actual: %v\n", s0, s1)
+		}
+	}
+}
+
+func TestStreamSampleWeighted(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var hits [3]int
+	for i := 0; i < 10000; i++ {
+		Of(0, 1, 2).SampleWeighted(1, func(v int) float64 { return float64(v) }, rng).
+			Foreach(func(v int) { hits[v]++ })
+	}
+	if hits[0] != 0 || hits[1] < 3000 || hits[1] > 3700 || hits[2] < 6300 || hits[2] > 7000 {
+		t.Fatalf("unexpected hits: %v\n", hits)
+	}
+}
+
+func TestStreamSampleFraction(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	cnt := Range(0, 10000).SampleFraction(0.25, rng).Count()
+	if cnt < 2300 || cnt > 2700 {
+		t.Fatalf("expected about 2500, actual: %v\n", cnt)
+	}
+	if Range(0, 100).SampleFraction(0, rng).Count() != 0 || Range(0, 100).SampleFraction(1, rng).Count() != 100 {
+		t.Fail()
+	}
+}
+
+func TestStreamShuffle(t *testing.T) {
+	slc := Range(0, 100).Shuffle(rand.New(rand.NewSource(1))).Collect()
+	if len(slc) != 100 {
+		t.Fatalf("expected: %v, actual: %v\n", 100, len(slc))
+	}
+	var moved bool
+	for i, v := range slc {
+		moved = moved || i != v
+	}
+	if !moved || len(ToSet(Slice(slc))) != 100 {
+		t.Fatalf("unexpected: %v\n", slc)
+	}
+}
@@ -0,0 +1,106 @@
+package stream
+
+// Kinds of TraceEvent.
+const (
+	TraceBegin     = "begin"
+	TraceAccept    = "accept"
+	TraceRejecting = "rejecting"
+	TraceClose     = "close"
+)
+
+// TraceEvent describes one call into the sink of a stage.
+type TraceEvent struct {
+	// Pipeline is the name given to WithTracer.
+	Pipeline string
+	// Stage and Kind identify the stage called into, as its index and Kind in the result of Describe.
+	Stage int
+	Kind  string
+	// Event is one of TraceBegin, TraceAccept, TraceRejecting and TraceClose.
+	Event string
+	// Size and Known are the arguments of a TraceBegin event.
+	Size  uint64
+	Known bool
+	// Seq is the 1-based sequence number of the element of a TraceAccept event in the stage, and Value is the element.
+	Seq   uint64
+	Value any
+	// Rejecting is the new state of a TraceRejecting event, which is only reported on transitions.
+	Rejecting bool
+}
+
+type traceConfig struct {
+	name        string
+	tracer      func(ev TraceEvent)
+	sampleEvery uint64
+}
+
+// WithTracer returns a new Stream[E] identical to s, except that every run of its pipeline, including stages
+// both before and after WithTracer, reports each Begin, Accept, Rejecting transition and Close of every stage
+// to tracer. Only one of every sampleEvery Accept events per stage is reported, and 0 means all.
+// Pipelines without a tracer are not affected at all.
+func WithTracer[E any](s Stream[E], name string, tracer func(ev TraceEvent), sampleEvery uint64) Stream[E] {
+	conf := &traceConfig{name: name, tracer: tracer, sampleEvery: Max(sampleEvery, 1)}
+	return configure(s, "trace", func(m *meta) *meta { return m.SetTrace(conf) })
+}
+
+// traceRun reports calls into the sinks of the stages of a segment in a run.
+type traceRun struct {
+	conf   *traceConfig
+	stages segmentStages
+}
+
+// wrap returns a tracedSink tracing the wrapped sink of the stage.
+func (r *traceRun) wrap(stage int, wrapped sink) sink {
+	ret := &tracedSink{inner: wrapped, conf: r.conf}
+	ret.stage, ret.kind = r.stages.index(stage)
+	return ret
+}
+
+type tracedSink struct {
+	inner     sink
+	conf      *traceConfig
+	stage     int
+	kind      string
+	seq       uint64
+	rejecting bool
+}
+
+func (s *tracedSink) event(event string) TraceEvent {
+	return TraceEvent{Pipeline: s.conf.name, Stage: s.stage, Kind: s.kind, Event: event}
+}
+
+func (s *tracedSink) Begin(size uint64, known bool) {
+	ev := s.event(TraceBegin)
+	ev.Size, ev.Known = size, known
+	s.conf.tracer(ev)
+	s.inner.Begin(size, known)
+}
+
+func (s *tracedSink) Accept(v any) {
+	s.seq++
+	if (s.seq-1)%s.conf.sampleEvery == 0 {
+		ev := s.event(TraceAccept)
+		ev.Seq, ev.Value = s.seq, v
+		s.conf.tracer(ev)
+	}
+	s.inner.Accept(v)
+}
+
+func (s *tracedSink) unwrap() sink {
+	return s.inner
+}
+
+func (s *tracedSink) Rejecting() bool {
+	rejecting := s.inner.Rejecting()
+	if rejecting != s.rejecting {
+		s.rejecting = rejecting
+		ev := s.event(TraceRejecting)
+		ev.Rejecting = rejecting
+		s.conf.tracer(ev)
+	}
+	return rejecting
+}
+
+func (s *tracedSink) Close() {
+	s.conf.tracer(s.event(TraceClose))
+	s.inner.Close()
+}
@@ -0,0 +1,31 @@
+//go:build go1.21
+
+package stream
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Trace is like WithTracer, but logs every TraceEvent to logger at debug level.
+func Trace[E any](s Stream[E], name string, logger *slog.Logger, sampleEvery uint64) Stream[E] {
+	return WithTracer(s, name, func(ev TraceEvent) {
+		if !logger.Enabled(context.Background(), slog.LevelDebug) {
+			return
+		}
+		attrs := []slog.Attr{
+			slog.String("pipeline", ev.Pipeline),
+			slog.Int("stage", ev.Stage),
+			slog.String("kind", ev.Kind),
+		}
+		switch ev.Event {
+		case TraceBegin:
+			attrs = append(attrs, slog.Uint64("size", ev.Size), slog.Bool("known", ev.Known))
+		case TraceAccept:
+			attrs = append(attrs, slog.Uint64("seq", ev.Seq), slog.Any("value", ev.Value))
+		case TraceRejecting:
+			attrs = append(attrs, slog.Bool("rejecting", ev.Rejecting))
+		}
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "gostream "+ev.Event, attrs...)
+	}, sampleEvery)
+}
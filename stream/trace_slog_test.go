@@ -0,0 +1,27 @@
+//go:build go1.21
+
+package stream
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	if cnt := Trace(Of(0, 1, 2), "test", logger, 0).Count(); cnt != 3 {
+		t.Fatalf("expected: %v, actual: %v\n", 3, cnt)
+	}
+	for _, s := range []string{
+		`msg="gostream begin" pipeline=test stage=1 kind=trace size=3 known=true`,
+		`msg="gostream accept" pipeline=test stage=2 kind=forCond seq=3 value=2`,
+		`msg="gostream close" pipeline=test stage=2 kind=forCond`,
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Fatalf("expected: %v, actual: %v\n", s, buf.String())
+		}
+	}
+}
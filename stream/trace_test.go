@@ -0,0 +1,44 @@
+package stream
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWithTracer(t *testing.T) {
+	var events []string
+	tracer := func(ev TraceEvent) {
+		switch ev.Event {
+		case TraceBegin:
+			events = append(events, fmt.Sprintf("%d %s begin %d %v", ev.Stage, ev.Kind, ev.Size, ev.Known))
+		case TraceAccept:
+			events = append(events, fmt.Sprintf("%d %s accept #%d %v", ev.Stage, ev.Kind, ev.Seq, ev.Value))
+		case TraceRejecting:
+			events = append(events, fmt.Sprintf("%d %s rejecting %v", ev.Stage, ev.Kind, ev.Rejecting))
+		case TraceClose:
+			events = append(events, fmt.Sprintf("%d %s close", ev.Stage, ev.Kind))
+		}
+	}
+	WithTracer(Of(0, 1, 2, 3), "test", tracer, 2).Limit(2).Foreach(func(int) {})
+	expected := []string{
+		"1 trace begin 4 true",
+		"2 limit begin 4 true",
+		"3 foreach begin 2 true",
+		"1 trace accept #1 0",
+		"2 limit accept #1 0",
+		"3 foreach accept #1 0",
+		"2 limit rejecting true",
+		"1 trace rejecting true",
+		"1 trace close",
+		"2 limit close",
+		"3 foreach close",
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected: %q, actual: %q\n", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("expected: %q, actual: %q\n", expected, events)
+		}
+	}
+}
@@ -1,5 +1,12 @@
 package stream
 
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"time"
+)
+
 type integer interface {
 	~int | ~int8 | ~int16 | ~int32 | ~int64
 }
@@ -42,3 +49,26 @@ func Min[E realNum](a, b E) E {
 	}
 	return b
 }
+
+// addSize adds two sizes, saturating at math.MaxUint64 on overflow.
+func addSize(a, b uint64) uint64 {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return math.MaxUint64
+	}
+	return sum
+}
+
+// mulSize multiplies two sizes, and ok equals false on overflow.
+func mulSize(a, b uint64) (n uint64, ok bool) {
+	hi, lo := bits.Mul64(a, b)
+	return lo, hi == 0
+}
+
+// newRand returns rng itself, or a new randomly seeded *rand.Rand if rng is nil.
+func newRand(rng *rand.Rand) *rand.Rand {
+	if rng != nil {
+		return rng
+	}
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
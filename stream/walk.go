@@ -0,0 +1,208 @@
+package stream
+
+import (
+	"errors"
+	"github.com/not2dim/gostream/iterator"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// WalkEntry is an entry of a file tree walked by Walk.
+type WalkEntry struct {
+	// Path is the path of the entry, which is root joined with the path relative to root like fs.WalkDir.
+	Path string
+	fs.DirEntry
+	skip *bool
+}
+
+// SkipDir skips the rest of the directory of the entry, or of its parent if it's not a directory. It takes effect
+// only if called before the next entry is walked, e.g. in Filter or Peek, but not after buffering stages like SortBy.
+func (e WalkEntry) SkipDir() {
+	*e.skip = true
+}
+
+// WalkOptions are options of Walk.
+type WalkOptions struct {
+	// MaxDepth limits the depth of entries to walk, where root is of depth 0 and its children are of depth 1.
+	// Entries of any depth are walked if MaxDepth <= 0.
+	MaxDepth int
+	// Include keeps only the files, i.e. non-directories, whose names match any of the patterns of path.Match.
+	// All files are kept if Include is empty.
+	Include []string
+	// Exclude drops the entries other than root, whose names match any of the patterns of path.Match, where
+	// excluded directories are not walked into.
+	Exclude []string
+	// OnError handles the error walking path, which stops the walk and fails the run unless OnError returns nil.
+	OnError func(path string, err error) error
+}
+
+// Walk returns a new Stream[WalkEntry] of the file tree rooted at root in fsys, which is walked by fs.WalkDir in
+// lexical order as elements are pulled, so that e.g. First or Limit stops walking the rest. The error stopping
+// the walk fails the run. Walk panics if any pattern of opts is malformed.
+func Walk(fsys fs.FS, root string, opts WalkOptions) Stream[WalkEntry] {
+	for _, pattern := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			panic("malformed pattern of Walk: " + pattern)
+		}
+	}
+	return Iterable[WalkEntry](walkIterable{fsys: fsys, root: root, opts: opts})
+}
+
+// matchAny reports whether name matches any of patterns, which are well-formed.
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// errWalkStopped stops fs.WalkDir once the walkIterator is closed.
+var errWalkStopped = errors.New("walk stopped")
+
+// region walkIterable
+
+type walkIterable struct {
+	fsys fs.FS
+	root string
+	opts WalkOptions
+}
+
+func (w walkIterable) Iterator() iterator.Iterator[WalkEntry] {
+	return &walkIterator{
+		walkIterable: w,
+		entries:      make(chan WalkEntry),
+		replies:      make(chan error),
+		done:         make(chan struct{}),
+	}
+}
+
+func (w walkIterable) Size() (n uint64, known bool) {
+	return 0, false
+}
+
+// depth returns the depth of p under root.
+func (w walkIterable) depth(p string) int {
+	if p == w.root {
+		return 0
+	}
+	rel := p
+	if w.root != "." {
+		rel = strings.TrimPrefix(strings.TrimPrefix(p, w.root), "/")
+	}
+	return strings.Count(rel, "/") + 1
+}
+
+// walkIterator runs fs.WalkDir on its own goroutine, which hands over one entry at a time, and waits for the reply
+// of the consumer on moving to the next entry, which is nil, fs.SkipDir or errWalkStopped.
+type walkIterator struct {
+	walkIterable
+	entries  chan WalkEntry
+	replies  chan error
+	done     chan struct{} // closed when the goroutine finishes.
+	started  bool
+	pending  bool // whether the goroutine waits for the reply.
+	finished bool
+	curr     WalkEntry
+	err      error // the error stopping the walk, written before done is closed.
+	panicked any   // written before done is closed.
+}
+
+func (w *walkIterator) walk() {
+	defer close(w.done)
+	defer func() {
+		w.panicked = recover()
+	}()
+	err := fs.WalkDir(w.fsys, w.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if w.opts.OnError == nil {
+				return err
+			}
+			return w.opts.OnError(p, err)
+		}
+		if p != w.root && matchAny(w.opts.Exclude, d.Name()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		depth := w.depth(p)
+		if !d.IsDir() && len(w.opts.Include) > 0 && !matchAny(w.opts.Include, d.Name()) {
+			return nil
+		}
+		skip := false
+		w.entries <- WalkEntry{Path: p, DirEntry: d, skip: &skip}
+		if reply := <-w.replies; reply != nil {
+			return reply
+		}
+		if d.IsDir() && w.opts.MaxDepth > 0 && depth >= w.opts.MaxDepth {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil && err != errWalkStopped {
+		w.err = err
+	}
+}
+
+func (w *walkIterator) MoveNext() bool {
+	if w.finished {
+		return false
+	}
+	if !w.started {
+		w.started = true
+		go w.walk()
+	} else if w.pending {
+		w.pending = false
+		var reply error
+		if *w.curr.skip {
+			reply = fs.SkipDir
+		}
+		w.replies <- reply
+	}
+	select {
+	case w.curr = <-w.entries:
+		w.pending = true
+		return true
+	case <-w.done:
+		w.finished = true
+		w.rethrow()
+		return false
+	}
+}
+
+func (w *walkIterator) Current() WalkEntry {
+	return w.curr
+}
+
+func (w *walkIterator) Close() {
+	if !w.started || w.finished {
+		return
+	}
+	w.finished = true
+	if w.pending {
+		w.pending = false
+		w.replies <- errWalkStopped
+	}
+	<-w.done
+	w.rethrow()
+}
+
+func (w *walkIterator) Err() error {
+	if w.finished {
+		return w.err
+	}
+	return nil
+}
+
+// rethrow re-raises the panic of the goroutine, e.g. of OnError, if any.
+func (w *walkIterator) rethrow() {
+	if r := w.panicked; r != nil {
+		w.panicked = nil
+		panic(r)
+	}
+}
+
+// endregion
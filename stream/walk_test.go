@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var walkFS = fstest.MapFS{
+	"go.mod":                 {Data: []byte("module x")},
+	"main.go":                {Data: []byte("package main")},
+	"README.md":              {Data: []byte("# x")},
+	"pkg/a/a.go":             {Data: []byte("package a")},
+	"pkg/a/a_test.go":        {Data: []byte("package a")},
+	"pkg/b/b.go":             {Data: []byte("package b")},
+	"vendor/dep/dep.go":      {Data: []byte("package dep")},
+	".git/objects/00/abcdef": {Data: []byte{}},
+}
+
+func walkPaths(s Stream[WalkEntry]) []string {
+	return Map(s, func(e WalkEntry) string { return e.Path }).Collect()
+}
+
+func TestWalk(t *testing.T) {
+	actual := walkPaths(Walk(walkFS, ".", WalkOptions{}))
+	expected := []string{".", ".git", ".git/objects", ".git/objects/00", ".git/objects/00/abcdef", "README.md",
+		"go.mod", "main.go", "pkg", "pkg/a", "pkg/a/a.go", "pkg/a/a_test.go", "pkg/b", "pkg/b/b.go", "vendor",
+		"vendor/dep", "vendor/dep/dep.go"}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: all, expected: %v, actual: %v\n", expected, actual)
+	}
+
+	actual = walkPaths(Walk(walkFS, ".", WalkOptions{Include: []string{"*.go"}, Exclude: []string{".*", "vendor", "*_test.go"}}).
+		Filter(func(e WalkEntry) bool { return !e.IsDir() }))
+	if expected := []string{"main.go", "pkg/a/a.go", "pkg/b/b.go"}; !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: globs, expected: %v, actual: %v\n", expected, actual)
+	}
+
+	actual = walkPaths(Walk(walkFS, "pkg", WalkOptions{MaxDepth: 1}))
+	if expected := []string{"pkg", "pkg/a", "pkg/b"}; !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: depth, expected: %v, actual: %v\n", expected, actual)
+	}
+
+	actual = walkPaths(Walk(walkFS, ".", WalkOptions{}).Peek(func(e WalkEntry) {
+		if e.IsDir() && (strings.HasPrefix(e.Name(), ".") && e.Path != "." || e.Name() == "pkg") {
+			e.SkipDir()
+		}
+	}).Filter(func(e WalkEntry) bool { return !e.IsDir() }))
+	if expected := []string{"README.md", "go.mod", "main.go", "vendor/dep/dep.go"}; !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: skip dir, expected: %v, actual: %v\n", expected, actual)
+	}
+
+	first := Walk(walkFS, ".", WalkOptions{Include: []string{"*.go"}}).Filter(func(e WalkEntry) bool {
+		return !e.IsDir()
+	}).First()
+	if !first.OK || first.Val.Path != "main.go" {
+		t.Fatalf("case: first, actual: %v\n", first)
+	}
+}
+
+func TestWalkErr(t *testing.T) {
+	if _, err := TryCollect(Walk(walkFS, "missing", WalkOptions{})); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("case: missing root, err: %v\n", err)
+	}
+	var reported []string
+	slc, err := TryCollect(Walk(walkFS, "missing", WalkOptions{OnError: func(path string, err error) error {
+		reported = append(reported, path)
+		return nil
+	}}))
+	if err != nil || len(slc) != 0 || !reflect.DeepEqual([]string{"missing"}, reported) {
+		t.Fatalf("case: on error, actual: %v, %v, %v\n", slc, err, reported)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("case: malformed pattern, expected a panic\n")
+		}
+	}()
+	Walk(walkFS, ".", WalkOptions{Include: []string{"["}})
+}
@@ -0,0 +1,270 @@
+package stream
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Window is a window of elements, whose times are in [Start, End).
+type Window[E any] struct {
+	Start, End time.Time
+	// Elems are ordered by their times, and by their arrivals for ties.
+	Elems []E
+}
+
+// WindowTime decides the time of each element for windows, returned by EventTime or ProcessingTime.
+type WindowTime[E any] struct {
+	extract  func(v E) time.Time
+	clock    Clock
+	lateness time.Duration
+	onLate   func(v E)
+}
+
+// EventTime returns a WindowTime taking extract(v) as the time of v. Windows are emitted once the watermark,
+// which is the latest time seen minus allowedLateness, passes their ends. Elements arriving after all their
+// windows are emitted are late, and are dropped and reported to onLate, which can be nil.
+func EventTime[E any](extract func(v E) time.Time, allowedLateness time.Duration, onLate func(v E)) WindowTime[E] {
+	return WindowTime[E]{extract: extract, lateness: allowedLateness, onLate: onLate}
+}
+
+// ProcessingTime returns a WindowTime taking the time of clock when v arrives as the time of v, where a nil clock
+// is replaced by the real one. Since nothing runs between arrivals, windows are emitted on the arrival of the first
+// element after their ends, or when the Stream ends.
+func ProcessingTime[E any](clock Clock) WindowTime[E] {
+	return WindowTime[E]{clock: newClock(clock)}
+}
+
+func (w WindowTime[E]) timeOf(v E) time.Time {
+	if w.extract != nil {
+		return w.extract(v)
+	}
+	return w.clock.Now()
+}
+
+// TumblingWindow groups elements into consecutive non-overlapping windows of duration d, aligned to the zero time.
+// Windows without elements are not emitted.
+func TumblingWindow[E any](s Stream[E], d time.Duration, wt WindowTime[E]) Stream[Window[E]] {
+	if d <= 0 {
+		panic("non-positive duration of TumblingWindow")
+	}
+	return window(s, windowKind{size: d, slide: d}, wt)
+}
+
+// SlidingWindow groups elements into windows of duration size starting every slide, aligned to the zero time,
+// so that each element belongs to about size/slide windows. Windows without elements are not emitted.
+// If slide > size, elements in the gaps between windows are dropped silently, without being reported as late.
+func SlidingWindow[E any](s Stream[E], size, slide time.Duration, wt WindowTime[E]) Stream[Window[E]] {
+	if size <= 0 || slide <= 0 {
+		panic("non-positive size or slide of SlidingWindow")
+	}
+	return window(s, windowKind{size: size, slide: slide}, wt)
+}
+
+// SessionWindow groups elements into sessions, each of which ends once no element comes within gap after its last
+// element, so a session window is [its first element, its last element + gap).
+func SessionWindow[E any](s Stream[E], gap time.Duration, wt WindowTime[E]) Stream[Window[E]] {
+	if gap <= 0 {
+		panic("non-positive gap of SessionWindow")
+	}
+	return window(s, windowKind{gap: gap}, wt)
+}
+
+func window[E any](s Stream[E], kind windowKind, wt WindowTime[E]) Stream[Window[E]] {
+	m := reflectBaseMeta(s)
+	if m.Empty() {
+		return newEmptyHeader[Window[E]]()
+	}
+	return newOpWindow(m.Copy(), reflectBaseCurr(s), kind, wt)
+}
+
+// windowKind is either tumbling or sliding windows with size and slide, or session windows with gap.
+type windowKind struct {
+	size, slide time.Duration
+	gap         time.Duration
+}
+
+// region Window
+
+type opWindow[E any] struct {
+	base[Window[E]]
+	kind windowKind
+	wt   WindowTime[E]
+}
+
+func newOpWindow[E any](meta *meta, upstream pipeline, kind windowKind, wt WindowTime[E]) (ret *opWindow[E]) {
+	ret = &opWindow[E]{kind: kind, wt: wt}
+	maxSize := meta.MaxSize()
+	if kind.gap == 0 {
+		perElem := uint64((kind.size + kind.slide - 1) / kind.slide)
+		if n, ok := mulSize(maxSize, perElem); ok {
+			maxSize = n
+		} else {
+			maxSize = math.MaxUint64
+		}
+	}
+	ret.base = base[Window[E]]{
+		Meta: meta.SetDistinct(false).SetSinkIterable(false).SetMaxSize(maxSize),
+		Prev: upstream, Curr: ret,
+	}
+	return
+}
+
+type openWindow[E any] struct {
+	start, end time.Time
+	times      []time.Time
+	elems      []E
+}
+
+func (w *openWindow[E]) add(t time.Time, v E) {
+	w.times = append(w.times, t)
+	w.elems = append(w.elems, v)
+}
+
+type windowSink[E any] struct {
+	baseSink
+	kind      windowKind
+	wt        WindowTime[E]
+	open      []*openWindow[E]
+	watermark time.Time
+	seen      bool
+}
+
+func (s *windowSink[E]) Begin(size uint64, _ bool) {
+	s.down.Begin(size, false)
+}
+
+func (s *windowSink[E]) Accept(v any) {
+	e := v.(E)
+	t := s.wt.timeOf(e)
+	if !s.assign(t, e) {
+		if s.wt.onLate != nil {
+			s.wt.onLate(e)
+		}
+		return
+	}
+	wm := t
+	if s.wt.extract != nil {
+		wm = t.Add(-s.wt.lateness)
+	}
+	if !s.seen || wm.After(s.watermark) {
+		s.seen, s.watermark = true, wm
+	}
+	s.fire(false)
+}
+
+// fired reports whether the window ending at end is emitted, or is to emit.
+func (s *windowSink[E]) fired(end time.Time) bool {
+	return s.seen && !end.After(s.watermark)
+}
+
+// assign adds v of time t into its windows, and returns false if v is late. v is not late but dropped if it's out
+// of all windows, e.g. in the gaps between sliding windows of slide > size.
+func (s *windowSink[E]) assign(t time.Time, v E) bool {
+	if s.kind.gap > 0 {
+		return s.assignSession(t, v)
+	}
+	var covered, assigned bool
+	for start := t.Truncate(s.kind.slide); start.Add(s.kind.size).After(t); start = start.Add(-s.kind.slide) {
+		end := start.Add(s.kind.size)
+		covered = true
+		if s.fired(end) {
+			continue
+		}
+		assigned = true
+		var w *openWindow[E]
+		for _, o := range s.open {
+			if o.start.Equal(start) {
+				w = o
+				break
+			}
+		}
+		if w == nil {
+			w = &openWindow[E]{start: start, end: end}
+			s.open = append(s.open, w)
+		}
+		w.add(t, v)
+	}
+	return assigned || !covered
+}
+
+func (s *windowSink[E]) assignSession(t time.Time, v E) bool {
+	w := &openWindow[E]{start: t, end: t.Add(s.kind.gap)}
+	if s.fired(w.end) {
+		return false
+	}
+	// merges all open sessions overlapping the new one.
+	var rest []*openWindow[E]
+	for _, o := range s.open {
+		if o.start.Before(w.end) && w.start.Before(o.end) {
+			if o.start.Before(w.start) {
+				w.start = o.start
+			}
+			if o.end.After(w.end) {
+				w.end = o.end
+			}
+			w.times = append(w.times, o.times...)
+			w.elems = append(w.elems, o.elems...)
+		} else {
+			rest = append(rest, o)
+		}
+	}
+	w.add(t, v)
+	s.open = append(rest, w)
+	return true
+}
+
+// fire emits windows passed by the watermark, or all windows if all is true, ordered by their ends and starts.
+func (s *windowSink[E]) fire(all bool) {
+	var ready, rest []*openWindow[E]
+	for _, w := range s.open {
+		if all || s.fired(w.end) {
+			ready = append(ready, w)
+		} else {
+			rest = append(rest, w)
+		}
+	}
+	s.open = rest
+	sort.Slice(ready, func(i, j int) bool {
+		if !ready[i].end.Equal(ready[j].end) {
+			return ready[i].end.Before(ready[j].end)
+		}
+		return ready[i].start.Before(ready[j].start)
+	})
+	for _, w := range ready {
+		if s.down.Rejecting() {
+			return
+		}
+		sort.Stable(byTime[E]{w})
+		s.down.Accept(Window[E]{Start: w.start, End: w.end, Elems: w.elems})
+	}
+}
+
+func (s *windowSink[E]) Close() {
+	s.fire(true)
+	s.down.Close()
+}
+
+func (f *opWindow[E]) WrapSink(down sink) sink {
+	return &windowSink[E]{baseSink: baseSink{down: down}, kind: f.kind, wt: f.wt}
+}
+
+// byTime sorts elements of an openWindow by their times.
+type byTime[E any] struct {
+	w *openWindow[E]
+}
+
+func (b byTime[E]) Len() int {
+	return len(b.w.elems)
+}
+
+func (b byTime[E]) Less(i, j int) bool {
+	return b.w.times[i].Before(b.w.times[j])
+}
+
+func (b byTime[E]) Swap(i, j int) {
+	b.w.times[i], b.w.times[j] = b.w.times[j], b.w.times[i]
+	b.w.elems[i], b.w.elems[j] = b.w.elems[j], b.w.elems[i]
+}
+
+// endregion
@@ -0,0 +1,108 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type event struct {
+	at  time.Duration // since epoch
+	val int
+}
+
+func eventTime(e event) time.Time {
+	return time.Unix(0, 0).Add(e.at)
+}
+
+// spans returns windows as [start, end) offsets in seconds along with their values.
+func spans(ws []Window[event]) (ret [][]int) {
+	for _, w := range ws {
+		span := []int{int(w.Start.Unix()), int(w.End.Unix())}
+		for _, e := range w.Elems {
+			span = append(span, e.val)
+		}
+		ret = append(ret, span)
+	}
+	return
+}
+
+func TestTumblingWindow(t *testing.T) {
+	s := time.Second
+	events := []event{{0, 0}, {1500 * time.Millisecond, 1}, {s, 2}, {4 * s, 3}, {2500 * time.Millisecond, 4}, {7 * s, 5}}
+	var late []int
+	actual := spans(TumblingWindow(Slice(events), 2*s, EventTime(eventTime, 0, func(e event) {
+		late = append(late, e.val)
+	})).Collect())
+	expected := [][]int{{0, 2, 0, 2, 1}, {4, 6, 3}, {6, 8, 5}}
+	if !reflect.DeepEqual(expected, actual) || !reflect.DeepEqual([]int{4}, late) {
+		t.Fatalf("case: no lateness, expected: %v, actual: %v, late: %v\n", expected, actual, late)
+	}
+
+	actual = spans(TumblingWindow(Slice(events), 2*s, EventTime(eventTime, 2*s, nil)).Collect())
+	expected = [][]int{{0, 2, 0, 2, 1}, {2, 4, 4}, {4, 6, 3}, {6, 8, 5}}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("case: allowed lateness, expected: %v, actual: %v\n", expected, actual)
+	}
+
+	var fired int
+	TumblingWindow(Slice(events), 2*s, EventTime(eventTime, 0, nil)).Peek(func(w Window[event]) {
+		fired++
+	}).Limit(1).Count()
+	if fired != 1 {
+		t.Fatalf("case: short-circuited, expected: %v, actual: %v\n", 1, fired)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	s := time.Second
+	events := []event{{0, 0}, {s, 1}, {3 * s, 2}}
+	actual := spans(SlidingWindow(Slice(events), 2*s, s, EventTime(eventTime, 0, nil)).Collect())
+	expected := [][]int{{-1, 1, 0}, {0, 2, 0, 1}, {1, 3, 1}, {2, 4, 2}, {3, 5, 2}}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected: %v, actual: %v\n", expected, actual)
+	}
+
+	var late []int
+	events = []event{{0, 0}, {1500 * time.Millisecond, 1}, {3 * s, 2}, {2 * s, 3}, {500 * time.Millisecond, 4}}
+	actual = spans(SlidingWindow(Slice(events), s, 3*s, EventTime(eventTime, 0, func(e event) {
+		late = append(late, e.val)
+	})).Collect())
+	expected = [][]int{{0, 1, 0}, {3, 4, 2}}
+	if !reflect.DeepEqual(expected, actual) || !reflect.DeepEqual([]int{4}, late) {
+		t.Fatalf("case: gaps, expected: %v, actual: %v, late: %v\n", expected, actual, late)
+	}
+}
+
+func TestSessionWindow(t *testing.T) {
+	s := time.Second
+	events := []event{{0, 0}, {s, 1}, {4 * s, 2}, {2500 * time.Millisecond, 3}, {10 * s, 4}, {5 * s, 5}}
+	var late []int
+	actual := spans(SessionWindow(Slice(events), 2*s, EventTime(eventTime, 3*s, func(e event) {
+		late = append(late, e.val)
+	})).Collect())
+	expected := [][]int{{0, 6, 0, 1, 3, 2}, {10, 12, 4}}
+	if !reflect.DeepEqual(expected, actual) || !reflect.DeepEqual([]int{5}, late) {
+		t.Fatalf("expected: %v, actual: %v, late: %v\n", expected, actual, late)
+	}
+}
+
+func TestProcessingTimeWindow(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	ch := make(chan int, 8)
+	for _, v := range []int{0, 1, 2, 3, 4} {
+		ch <- v
+	}
+	close(ch)
+	gaps := []time.Duration{0, 500, 2000, 100, 3000}
+	var actual [][]int
+	TumblingWindow(FromChan(ch).Peek(func(v int) {
+		clock.Advance(gaps[v] * time.Millisecond)
+	}), time.Second, ProcessingTime[int](clock)).Foreach(func(w Window[int]) {
+		actual = append(actual, append([]int{int(w.Start.Unix())}, w.Elems...))
+	})
+	expected := [][]int{{0, 0, 1}, {2, 2, 3}, {5, 4}}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected: %v, actual: %v\n", expected, actual)
+	}
+}